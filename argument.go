@@ -2,50 +2,63 @@ package graphb
 
 import (
 	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
 )
 
-type argumentValue interface {
-	stringChan() <-chan string
+// Value is a GraphQL input value, such as the value of an Argument or the default value of a variable.
+// The Value... functions and Null return the built-in values. A custom scalar, such as a DateTime or a Money type,
+// implements Value to render itself; Literal returns its GraphQL literal, e.g. "2018-01-01" with quotes.
+// ArgumentAny, ValueList and ValueObject use a Value as it is.
+type Value interface {
+	Literal() string
 }
 
 type Argument struct {
 	Name  string
-	Value argumentValue
+	Value Value
 }
 
-func (a *Argument) stringChan() <-chan string {
-	tokenChan := make(chan string)
-	go func() {
-		tokenChan <- a.Name
-		tokenChan <- ":"
-		for str := range a.Value.stringChan() {
-			tokenChan <- str
-		}
-		close(tokenChan)
-	}()
-	return tokenChan
+func (a *Argument) tokens(sink tokenSink) {
+	sink(a.Name)
+	sink(":")
+	valueTokens(a.Value, sink)
 }
 
+// ArgumentAny converts a Go value to an argument of given name. It supports
+//   - nil, nil pointers and nil maps as null; other pointers and interfaces as the value they refer to
+//   - bool, every integer kind and float kind, and string, including named types such as type Order string
+//   - json.Number as a number
+//   - slices and arrays as lists, at any depth
+//   - maps with string keys as custom types (input objects) whose fields are ordered by key
+//   - structs as custom types whose fields are the exported struct fields in declaration order. Embedded structs are
+//     flattened, and graphql:"name,omitempty,enum" tags rename a field, omit it when empty or render its strings as
+//     enum values. A field tagged graphql:"-" is skipped
+//   - values implementing Value, including the values returned by the Value... functions and Null
+//   - values implementing json.Marshaler as the GraphQL value matching their JSON, e.g. {"a":[1]} as {a:[1]}
+//   - values implementing encoding.TextMarshaler, such as net.IP, as strings
+//
+// These interfaces take precedence over the conversion by kind. A value implementing several of them uses the first
+// in the order Value, json.Marshaler and encoding.TextMarshaler, so time.Time is converted by its MarshalJSON.
+// Like encoding/json, a method of a pointer receiver is used when the value is addressable, e.g. a struct field of a pointer.
+//
+// An ArgumentTypeNotSupportedErr reports the path to the unsupported value, e.g. input.items[2].price.
 func ArgumentAny(name string, value interface{}) (Argument, error) {
-	switch v := value.(type) {
-	case bool:
-		return ArgumentBool(name, v), nil
-	case []bool:
-		return ArgumentBoolSlice(name, v...), nil
-
-	case int:
-		return ArgumentInt(name, v), nil
-	case []int:
-		return ArgumentIntSlice(name, v...), nil
-
-	case string:
-		return ArgumentString(name, v), nil
-	case []string:
-		return ArgumentStringSlice(name, v...), nil
-
-	default:
-		return Argument{}, ArgumentTypeNotSupportedErr{Value: value}
+	v, err := valueOf(value, name)
+	if err != nil {
+		return Argument{}, err
 	}
+	return Argument{name, v}, nil
+}
+
+// ArgumentNull returns an argument whose value is explicitly null, e.g. to clear a field in an update mutation.
+// An explicit null is different from leaving the argument out.
+func ArgumentNull(name string) Argument {
+	return Argument{name, argNull{}}
 }
 
 func ArgumentBool(name string, value bool) Argument {
@@ -56,10 +69,20 @@ func ArgumentInt(name string, value int) Argument {
 	return Argument{name, argInt(value)}
 }
 
+// ArgumentFloat returns a float argument. NaN and infinities are not valid GraphQL values and fail the check of the Query.
+func ArgumentFloat(name string, value float64) Argument {
+	return Argument{name, argFloat(value)}
+}
+
 func ArgumentString(name string, value string) Argument {
 	return Argument{name, argString(value)}
 }
 
+// ArgumentEnum returns an argument whose value is an enum value, which is rendered unquoted, e.g. order:DESC.
+func ArgumentEnum(name string, value string) Argument {
+	return Argument{name, argEnum(value)}
+}
+
 func ArgumentBoolSlice(name string, values ...bool) Argument {
 	return Argument{name, argBoolSlice(values)}
 }
@@ -68,10 +91,29 @@ func ArgumentIntSlice(name string, values ...int) Argument {
 	return Argument{name, argIntSlice(values)}
 }
 
+func ArgumentFloatSlice(name string, values ...float64) Argument {
+	return Argument{name, argFloatSlice(values)}
+}
+
 func ArgumentStringSlice(name string, values ...string) Argument {
 	return Argument{name, argStringSlice(values)}
 }
 
+func ArgumentEnumSlice(name string, values ...string) Argument {
+	return Argument{name, argEnumSlice(values)}
+}
+
+// ArgumentVariable returns an argument whose value is a reference to the operation variable varName, e.g. id:$id.
+// varName does not include the leading $.
+func ArgumentVariable(name string, varName string) Argument {
+	return Argument{name, argVariable(varName)}
+}
+
+// ArgumentVariableSlice returns an argument whose value is a list of references to operation variables.
+func ArgumentVariableSlice(name string, varNames ...string) Argument {
+	return Argument{name, argVariableSlice(varNames)}
+}
+
 // ArgumentCustomType returns a custom GraphQL type's argument representation, which could be a recursive structure.
 func ArgumentCustomType(name string, values ...Argument) Argument {
 	return Argument{name, argumentSlice(values)}
@@ -85,44 +127,185 @@ func ArgumentCustomTypeSliceElem(values ...Argument) []Argument {
 	return values
 }
 
+// ArgumentList returns an argument whose value is a list of any values, including other lists.
+// For example, a [[Int]] matrix:
+//
+//	ArgumentList("matrix", ValueList(ValueInt(1), ValueInt(2)), ValueList(ValueInt(3), Null))
+func ArgumentList(name string, values ...Value) Argument {
+	return Argument{name, argList(values)}
+}
+
+////////////////////////
+// Value Constructors //
+////////////////////////
+
+// ValueBool returns a boolean value, which can be an item of ValueList and ArgumentList.
+func ValueBool(value bool) Value {
+	return argBool(value)
+}
+
+// ValueInt returns an integer value, which can be an item of ValueList and ArgumentList.
+func ValueInt(value int) Value {
+	return argInt(value)
+}
+
+// ValueFloat returns a float value, which can be an item of ValueList and ArgumentList.
+func ValueFloat(value float64) Value {
+	return argFloat(value)
+}
+
+// ValueString returns a string value, which can be an item of ValueList and ArgumentList.
+func ValueString(value string) Value {
+	return argString(value)
+}
+
+// ValueEnum returns an enum value, which can be an item of ValueList and ArgumentList.
+func ValueEnum(value string) Value {
+	return argEnum(value)
+}
+
+// ValueVariable returns a reference to an operation variable, which can be an item of ValueList and ArgumentList.
+func ValueVariable(varName string) Value {
+	return argVariable(varName)
+}
+
+// ValueObject returns a custom type's value, which can be an item of ValueList and ArgumentList.
+func ValueObject(fields ...Argument) Value {
+	return argumentSlice(fields)
+}
+
+// ValueList returns a list value of any values, including other lists, which can be an item of ValueList and ArgumentList.
+func ValueList(values ...Value) Value {
+	return argList(values)
+}
+
 /////////////////////////////
 // Primitive Wrapper Types //
 /////////////////////////////
 
+// Null is the GraphQL null value. It can be passed to ArgumentAny and used as the default value of a variable,
+// where a nil DefaultValue means no default value at all.
+var Null = argNull{}
+
+// argNull represents the null value.
+type argNull struct{}
+
+func (v argNull) StringChan() <-chan string {
+	return tokenChan(v.tokens)
+}
+
+func (v argNull) Literal() string {
+	return tokenString(v.tokens)
+}
+
+func (v argNull) tokens(sink tokenSink) {
+	sink("null")
+}
+
 // argBool represents a boolean value.
 type argBool bool
 
-func (v argBool) stringChan() <-chan string {
-	tokenChan := make(chan string)
-	go func() {
-		tokenChan <- fmt.Sprintf("%t", v)
-		close(tokenChan)
-	}()
-	return tokenChan
+func (v argBool) StringChan() <-chan string {
+	return tokenChan(v.tokens)
+}
+
+func (v argBool) Literal() string {
+	return tokenString(v.tokens)
+}
+
+func (v argBool) tokens(sink tokenSink) {
+	sink(strconv.FormatBool(bool(v)))
 }
 
 // argInt represents an integer value.
 type argInt int
 
-func (v argInt) stringChan() <-chan string {
-	tokenChan := make(chan string)
-	go func() {
-		tokenChan <- fmt.Sprintf("%d", v)
-		close(tokenChan)
-	}()
-	return tokenChan
+func (v argInt) StringChan() <-chan string {
+	return tokenChan(v.tokens)
+}
+
+func (v argInt) Literal() string {
+	return tokenString(v.tokens)
+}
+
+func (v argInt) tokens(sink tokenSink) {
+	sink(strconv.Itoa(int(v)))
+}
+
+// argFloat represents a float value.
+type argFloat float64
+
+func (v argFloat) StringChan() <-chan string {
+	return tokenChan(v.tokens)
+}
+
+func (v argFloat) Literal() string {
+	return tokenString(v.tokens)
+}
+
+func (v argFloat) tokens(sink tokenSink) {
+	sink(formatFloat(float64(v)))
+}
+
+// argNumber represents an Int or Float value by its literal, e.g. a large uint64 or a number marshaled to JSON.
+type argNumber string
+
+func (v argNumber) StringChan() <-chan string {
+	return tokenChan(v.tokens)
+}
+
+func (v argNumber) Literal() string {
+	return tokenString(v.tokens)
+}
+
+func (v argNumber) tokens(sink tokenSink) {
+	sink(string(v))
 }
 
 // argString represents a string value.
 type argString string
 
-func (v argString) stringChan() <-chan string {
-	tokenChan := make(chan string)
-	go func() {
-		tokenChan <- fmt.Sprintf(`"%s"`, v)
-		close(tokenChan)
-	}()
-	return tokenChan
+func (v argString) StringChan() <-chan string {
+	return tokenChan(v.tokens)
+}
+
+func (v argString) Literal() string {
+	return tokenString(v.tokens)
+}
+
+func (v argString) tokens(sink tokenSink) {
+	sink(quoteString(string(v)))
+}
+
+// argEnum represents an enum value.
+type argEnum string
+
+func (v argEnum) StringChan() <-chan string {
+	return tokenChan(v.tokens)
+}
+
+func (v argEnum) Literal() string {
+	return tokenString(v.tokens)
+}
+
+func (v argEnum) tokens(sink tokenSink) {
+	sink(string(v))
+}
+
+// argVariable represents a reference to an operation variable.
+type argVariable string
+
+func (v argVariable) StringChan() <-chan string {
+	return tokenChan(v.tokens)
+}
+
+func (v argVariable) Literal() string {
+	return tokenString(v.tokens)
+}
+
+func (v argVariable) tokens(sink tokenSink) {
+	sink(tokenDollar)
+	sink(string(v))
 }
 
 //////////////////////////////////
@@ -132,96 +315,374 @@ func (v argString) stringChan() <-chan string {
 // argBoolSlice implements valueSlice
 type argBoolSlice []bool
 
-func (s argBoolSlice) stringChan() <-chan string {
-	tokenChan := make(chan string)
-	go func() {
-		tokenChan <- "["
-		for i, v := range s {
-			if i != 0 {
-				tokenChan <- ","
-			}
-			tokenChan <- fmt.Sprintf("%t", v)
+func (s argBoolSlice) StringChan() <-chan string {
+	return tokenChan(s.tokens)
+}
+
+func (s argBoolSlice) Literal() string {
+	return tokenString(s.tokens)
+}
+
+func (s argBoolSlice) tokens(sink tokenSink) {
+	sink(tokenLSB)
+	for i, v := range s {
+		if i != 0 {
+			sink(tokenComma)
 		}
-		tokenChan <- "]"
-		close(tokenChan)
-	}()
-	return tokenChan
+		sink(strconv.FormatBool(v))
+	}
+	sink(tokenRSB)
 }
 
 // argIntSlice implements valueSlice
 type argIntSlice []int
 
-func (s argIntSlice) stringChan() <-chan string {
-	tokenChan := make(chan string)
-	go func() {
-		tokenChan <- "["
-		for i, v := range s {
-			if i != 0 {
-				tokenChan <- ","
-			}
-			tokenChan <- fmt.Sprintf("%d", v)
+func (s argIntSlice) StringChan() <-chan string {
+	return tokenChan(s.tokens)
+}
+
+func (s argIntSlice) Literal() string {
+	return tokenString(s.tokens)
+}
+
+func (s argIntSlice) tokens(sink tokenSink) {
+	sink(tokenLSB)
+	for i, v := range s {
+		if i != 0 {
+			sink(tokenComma)
 		}
-		tokenChan <- "]"
-		close(tokenChan)
-	}()
-	return tokenChan
+		sink(strconv.Itoa(v))
+	}
+	sink(tokenRSB)
+}
+
+// argFloatSlice implements valueSlice
+type argFloatSlice []float64
+
+func (s argFloatSlice) StringChan() <-chan string {
+	return tokenChan(s.tokens)
+}
+
+func (s argFloatSlice) Literal() string {
+	return tokenString(s.tokens)
+}
+
+func (s argFloatSlice) tokens(sink tokenSink) {
+	sink(tokenLSB)
+	for i, v := range s {
+		if i != 0 {
+			sink(tokenComma)
+		}
+		sink(formatFloat(v))
+	}
+	sink(tokenRSB)
 }
 
 // argStringSlice implements valueSlice
 type argStringSlice []string
 
-func (s argStringSlice) stringChan() <-chan string {
-	tokenChan := make(chan string)
-	go func() {
-		tokenChan <- "["
-		for i, v := range s {
-			if i != 0 {
-				tokenChan <- ","
-			}
-			tokenChan <- fmt.Sprintf(`"%s"`, v)
+func (s argStringSlice) StringChan() <-chan string {
+	return tokenChan(s.tokens)
+}
+
+func (s argStringSlice) Literal() string {
+	return tokenString(s.tokens)
+}
+
+func (s argStringSlice) tokens(sink tokenSink) {
+	sink(tokenLSB)
+	for i, v := range s {
+		if i != 0 {
+			sink(tokenComma)
+		}
+		sink(quoteString(v))
+	}
+	sink(tokenRSB)
+}
+
+// argEnumSlice implements valueSlice
+type argEnumSlice []string
+
+func (s argEnumSlice) StringChan() <-chan string {
+	return tokenChan(s.tokens)
+}
+
+func (s argEnumSlice) Literal() string {
+	return tokenString(s.tokens)
+}
+
+func (s argEnumSlice) tokens(sink tokenSink) {
+	sink(tokenLSB)
+	for i, v := range s {
+		if i != 0 {
+			sink(tokenComma)
+		}
+		sink(v)
+	}
+	sink(tokenRSB)
+}
+
+// argVariableSlice implements valueSlice
+type argVariableSlice []string
+
+func (s argVariableSlice) StringChan() <-chan string {
+	return tokenChan(s.tokens)
+}
+
+func (s argVariableSlice) Literal() string {
+	return tokenString(s.tokens)
+}
+
+func (s argVariableSlice) tokens(sink tokenSink) {
+	sink(tokenLSB)
+	for i, v := range s {
+		if i != 0 {
+			sink(tokenComma)
 		}
-		tokenChan <- "]"
-		close(tokenChan)
-	}()
-	return tokenChan
+		sink(tokenDollar)
+		sink(v)
+	}
+	sink(tokenRSB)
+}
+
+// argList represents a list of any values, including other lists.
+type argList []Value
+
+func (s argList) StringChan() <-chan string {
+	return tokenChan(s.tokens)
+}
+
+func (s argList) Literal() string {
+	return tokenString(s.tokens)
+}
+
+func (s argList) tokens(sink tokenSink) {
+	sink(tokenLSB)
+	for i, v := range s {
+		if i != 0 {
+			sink(tokenComma)
+		}
+		valueTokens(v, sink)
+	}
+	sink(tokenRSB)
 }
 
 type argumentSlice []Argument
 
-func (s argumentSlice) stringChan() <-chan string {
-	tokenChan := make(chan string)
-	go func() {
-		tokenChan <- "{"
-		for i, v := range s {
-			if i != 0 {
-				tokenChan <- ","
-			}
-			for str := range v.stringChan() {
-				tokenChan <- str
-			}
+func (s argumentSlice) StringChan() <-chan string {
+	return tokenChan(s.tokens)
+}
+
+func (s argumentSlice) Literal() string {
+	return tokenString(s.tokens)
+}
+
+func (s argumentSlice) tokens(sink tokenSink) {
+	sink(tokenLB)
+	for i := range s {
+		if i != 0 {
+			sink(tokenComma)
 		}
-		tokenChan <- "}"
-		close(tokenChan)
-	}()
-	return tokenChan
+		s[i].tokens(sink)
+	}
+	sink(tokenRB)
 }
 
 type argCustomTypeSlice [][]Argument
 
-func (s argCustomTypeSlice) stringChan() <-chan string {
-	tokenChan := make(chan string)
-	go func() {
-		tokenChan <- "["
-		for i, v := range s {
-			if i != 0 {
-				tokenChan <- ","
+func (s argCustomTypeSlice) StringChan() <-chan string {
+	return tokenChan(s.tokens)
+}
+
+func (s argCustomTypeSlice) Literal() string {
+	return tokenString(s.tokens)
+}
+
+func (s argCustomTypeSlice) tokens(sink tokenSink) {
+	sink(tokenLSB)
+	for i, v := range s {
+		if i != 0 {
+			sink(tokenComma)
+		}
+		argumentSlice(v).tokens(sink)
+	}
+	sink(tokenRSB)
+}
+
+/////////////
+// Helpers //
+/////////////
+
+// valueTokens prints the tokens of v. A Value other than the built-in ones is printed as a single token of its Literal.
+func valueTokens(v Value, sink tokenSink) {
+	if p, ok := v.(interface{ tokens(tokenSink) }); ok {
+		p.tokens(sink)
+		return
+	}
+	sink(v.Literal())
+}
+
+// checkValue checks the names used inside of a value, which are argument names of custom types and variable names.
+// A nil value or a nil pointer of a custom Value, at any depth, is not allowed.
+func checkValue(value Value) error {
+	if rv := reflect.ValueOf(value); !rv.IsValid() || rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return errors.WithStack(NilValueErr{})
+	}
+	switch v := value.(type) {
+	case argFloat:
+		return errors.WithStack(checkFloat(float64(v)))
+	case argFloatSlice:
+		for _, f := range v {
+			if err := checkFloat(f); err != nil {
+				return errors.WithStack(err)
 			}
-			for str := range argumentSlice(v).stringChan() {
-				tokenChan <- str
+		}
+	case argEnum:
+		return errors.WithStack(checkEnum(string(v)))
+	case argEnumSlice:
+		for _, e := range v {
+			if err := checkEnum(e); err != nil {
+				return errors.WithStack(err)
 			}
 		}
-		tokenChan <- "]"
-		close(tokenChan)
-	}()
-	return tokenChan
+	case argVariable:
+		if !validName.MatchString(string(v)) {
+			return errors.WithStack(InvalidNameErr{variableName, string(v)})
+		}
+	case argVariableSlice:
+		for _, name := range v {
+			if err := checkValue(argVariable(name)); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+	case argumentSlice:
+		for _, arg := range v {
+			if !validName.MatchString(arg.Name) {
+				return errors.WithStack(InvalidNameErr{argumentName, arg.Name})
+			}
+			if err := checkValue(arg.Value); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+	case argCustomTypeSlice:
+		for _, elem := range v {
+			if err := checkValue(argumentSlice(elem)); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+	case argList:
+		for _, elem := range v {
+			if err := checkValue(elem); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+	}
+	return nil
+}
+
+// valueVariables returns the names of all variables referenced by a value in order of appearance.
+func valueVariables(value Value) []string {
+	switch v := value.(type) {
+	case argVariable:
+		return []string{string(v)}
+	case argVariableSlice:
+		return v
+	case argumentSlice:
+		var names []string
+		for _, arg := range v {
+			names = append(names, valueVariables(arg.Value)...)
+		}
+		return names
+	case argCustomTypeSlice:
+		var names []string
+		for _, elem := range v {
+			names = append(names, valueVariables(argumentSlice(elem))...)
+		}
+		return names
+	case argList:
+		var names []string
+		for _, elem := range v {
+			names = append(names, valueVariables(elem)...)
+		}
+		return names
+	}
+	return nil
+}
+
+// checkEnum checks that an enum value is a name but not true, false or null.
+func checkEnum(v string) error {
+	if !validName.MatchString(v) || v == "true" || v == "false" || v == "null" {
+		return errors.WithStack(InvalidEnumValueErr{v})
+	}
+	return nil
+}
+
+// quoteString returns s as a GraphQL string value.
+// Quotes, backslashes and control characters are escaped and invalid UTF-8 is replaced by U+FFFD,
+// so that the value can not break out of the string. See: http://facebook.github.io/graphql/October2016/#sec-String-Value
+func quoteString(s string) string {
+	var b strings.Builder
+	b.Grow(len(s) + 2)
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04X`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+func checkFloat(v float64) error {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return errors.WithStack(InvalidFloatErr{v})
+	}
+	return nil
+}
+
+// formatFloat formats a float the way encoding/json does, which is a valid GraphQL float value.
+// Exponent notation is used for very large and very small numbers and integral values get a fraction part, e.g. 1.0.
+func formatFloat(v float64) string {
+	abs := math.Abs(v)
+	format := byte('f')
+	if abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+	s := strconv.FormatFloat(v, format, -1, 64)
+	if format == 'e' {
+		// clean up e-09 to e-9
+		n := len(s)
+		if n >= 4 && s[n-4] == 'e' && s[n-3] == '-' && s[n-2] == '0' {
+			s = s[:n-2] + s[n-1:]
+		}
+	} else if !strings.Contains(s, ".") {
+		s += ".0"
+	}
+	return s
+}
+
+// float32To64 converts a float32 to the float64 of the shortest decimal representation of the float32,
+// so that float32(0.1) is formatted as 0.1 rather than 0.10000000149011612.
+func float32To64(v float32) float64 {
+	f, _ := strconv.ParseFloat(strconv.FormatFloat(float64(v), 'g', -1, 32), 64)
+	return f
 }
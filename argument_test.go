@@ -1,8 +1,13 @@
 package graphb
 
 import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -31,10 +36,26 @@ func TestArgumentAny(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, Argument{"arg", argIntSlice([]int{1, 2})}, arg)
 
-	// Type Not Supported
 	arg, err = ArgumentAny("arg", 1.1)
+	assert.Nil(t, err)
+	assert.Equal(t, Argument{"arg", argFloat(1.1)}, arg)
+
+	arg, err = ArgumentAny("arg", float32(0.1))
+	assert.Nil(t, err)
+	assert.Equal(t, Argument{"arg", argFloat(0.1)}, arg)
+
+	arg, err = ArgumentAny("arg", []float64{1, 2.5})
+	assert.Nil(t, err)
+	assert.Equal(t, Argument{"arg", argFloatSlice([]float64{1, 2.5})}, arg)
+
+	arg, err = ArgumentAny("arg", []float32{0.1})
+	assert.Nil(t, err)
+	assert.Equal(t, Argument{"arg", argFloatSlice([]float64{0.1})}, arg)
+
+	// Type Not Supported
+	arg, err = ArgumentAny("arg", 1+2i)
 	assert.IsType(t, ArgumentTypeNotSupportedErr{}, err)
-	assert.Equal(t, "Argument 1.1 of Type float64 is not supported", err.Error())
+	assert.Equal(t, "Argument (1+2i) of Type complex128 is not supported", err.Error())
 	assert.Equal(t, Argument{}, arg)
 }
 
@@ -69,7 +90,7 @@ func TestArgumentStringSlice(t *testing.T) {
 func Test_argBool(t *testing.T) {
 	b := argBool(true)
 	i := 0
-	for str := range b.stringChan() {
+	for str := range b.StringChan() {
 		assert.Equal(t, "true", str)
 		i++
 	}
@@ -78,7 +99,7 @@ func Test_argBool(t *testing.T) {
 
 func Test_argBoolSlice(t *testing.T) {
 	bs := argBoolSlice([]bool{true, false})
-	c := bs.stringChan()
+	c := bs.StringChan()
 	i := 0
 	tokens := []string{"[", "true", ",", "false", "]"}
 	for str, ok := <-c; ok; str, ok = <-c {
@@ -92,9 +113,281 @@ func Test_argIntSlice(t *testing.T) {
 	is := argIntSlice([]int{1, -1, 0})
 	tokens := []string{"[", "1", ",", "-1", ",", "0", "]"}
 	i := 0
-	for str := range is.stringChan() {
+	for str := range is.StringChan() {
 		assert.Equal(t, tokens[i], str)
 		i++
 	}
 	assert.Equal(t, len(tokens), i)
 }
+
+func TestArgumentVariable(t *testing.T) {
+	a := ArgumentVariable("id", "userId")
+	assert.Equal(t, Argument{"id", argVariable("userId")}, a)
+	assert.Equal(t, "id:$userId", tokenString(a.tokens))
+
+	a = ArgumentVariableSlice("ids", "a", "b")
+	assert.Equal(t, "ids:[$a,$b]", tokenString(a.tokens))
+
+	a = ArgumentCustomTypeSlice("filters", ArgumentCustomTypeSliceElem(ArgumentVariable("id", "id")))
+	assert.Equal(t, "filters:[{id:$id}]", tokenString(a.tokens))
+	assert.Equal(t, []string{"id"}, valueVariables(a.Value))
+}
+
+func Test_checkValue(t *testing.T) {
+	assert.Nil(t, checkValue(argVariable("id")))
+	assert.IsType(t, InvalidNameErr{}, errors.Cause(checkValue(argVariable("$id"))))
+	assert.IsType(t, InvalidNameErr{}, errors.Cause(checkValue(argVariableSlice{"a", "1"})))
+	assert.IsType(t, InvalidNameErr{}, errors.Cause(checkValue(argumentSlice{ArgumentInt("a b", 1)})))
+	assert.IsType(t, InvalidNameErr{}, errors.Cause(checkValue(argCustomTypeSlice{{ArgumentVariable("a", "")}})))
+}
+
+func TestArgumentFloat(t *testing.T) {
+	a := ArgumentFloat("price", 9.99)
+	assert.Equal(t, Argument{"price", argFloat(9.99)}, a)
+	assert.Equal(t, "price:9.99", tokenString(a.tokens))
+
+	a = ArgumentFloatSlice("point", 1, -2.5)
+	assert.Equal(t, "point:[1.0,-2.5]", tokenString(a.tokens))
+
+	a = ArgumentCustomType("input", ArgumentFloat("lat", 52.52), ArgumentFloat("lng", 13.405))
+	assert.Equal(t, "input:{lat:52.52,lng:13.405}", tokenString(a.tokens))
+}
+
+func Test_formatFloat(t *testing.T) {
+	for v, s := range map[float64]string{
+		0:                    "0.0",
+		math.Copysign(0, -1): "-0.0",
+		1:                    "1.0",
+		-3:                   "-3.0",
+		0.5:                  "0.5",
+		1e20:                 "100000000000000000000.0",
+		1e21:                 "1e+21",
+		1.5e300:              "1.5e+300",
+		1e-6:                 "0.000001",
+		1e-7:                 "1e-7",
+		-2.5e-10:             "-2.5e-10",
+		123.456:              "123.456",
+	} {
+		assert.Equal(t, s, formatFloat(v))
+	}
+}
+
+func Test_checkFloat(t *testing.T) {
+	assert.Nil(t, checkValue(argFloat(1)))
+	for _, v := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		assert.IsType(t, InvalidFloatErr{}, errors.Cause(checkValue(argFloat(v))))
+		assert.IsType(t, InvalidFloatErr{}, errors.Cause(checkValue(argFloatSlice{1, v})))
+		assert.IsType(t, InvalidFloatErr{}, errors.Cause(checkValue(argumentSlice{ArgumentFloat("f", v)})))
+	}
+	_, err := MakeField("f").SetArguments(ArgumentFloat("f", math.NaN())).StringChan()
+	assert.Equal(t, "Float NaN is not a valid GraphQL value", err.Error())
+}
+
+func TestArgumentNull(t *testing.T) {
+	a := ArgumentNull("avatar")
+	assert.Equal(t, Argument{"avatar", argNull{}}, a)
+	assert.Equal(t, "avatar:null", tokenString(a.tokens))
+
+	a = ArgumentCustomType("input", ArgumentString("name", "x"), ArgumentNull("avatar"))
+	assert.Equal(t, `input:{name:"x",avatar:null}`, tokenString(a.tokens))
+
+	for _, v := range []interface{}{nil, Null, (*int)(nil), (*string)(nil)} {
+		arg, err := ArgumentAny("avatar", v)
+		assert.Nil(t, err)
+		assert.Equal(t, ArgumentNull("avatar"), arg)
+	}
+
+	i, s := 1, "s"
+	arg, err := ArgumentAny("arg", &i)
+	assert.Nil(t, err)
+	assert.Equal(t, ArgumentInt("arg", 1), arg)
+	arg, err = ArgumentAny("arg", &s)
+	assert.Nil(t, err)
+	assert.Equal(t, ArgumentString("arg", "s"), arg)
+}
+
+func TestArgumentEnum(t *testing.T) {
+	a := ArgumentEnum("order", "DESC")
+	assert.Equal(t, Argument{"order", argEnum("DESC")}, a)
+	assert.Equal(t, "order:DESC", tokenString(a.tokens))
+
+	a = ArgumentEnumSlice("states", "OPEN", "MERGED")
+	assert.Equal(t, "states:[OPEN,MERGED]", tokenString(a.tokens))
+
+	a = ArgumentCustomType("orderBy", ArgumentEnum("field", "CREATED_AT"), ArgumentEnum("direction", "ASC"))
+	assert.Equal(t, "orderBy:{field:CREATED_AT,direction:ASC}", tokenString(a.tokens))
+	assert.Nil(t, checkValue(a.Value))
+
+	for _, v := range []string{"true", "false", "null", "", "1ST", "DE SC"} {
+		err := checkValue(argEnum(v))
+		assert.IsType(t, InvalidEnumValueErr{}, errors.Cause(err))
+		err = checkValue(argCustomTypeSlice{{ArgumentEnumSlice("e", "A", v)}})
+		assert.IsType(t, InvalidEnumValueErr{}, errors.Cause(err))
+	}
+	_, err := MakeField("f").SetArguments(ArgumentEnum("order", "null")).StringChan()
+	assert.Equal(t, "'null' is an invalid enum value in GraphQL. A valid enum value is a name but not true, false or null", err.Error())
+}
+
+func Test_quoteString(t *testing.T) {
+	for s, quoted := range map[string]string{
+		"":                  `""`,
+		"plain":             `"plain"`,
+		`say "hi"`:          `"say \"hi\""`,
+		`C:\dir`:            `"C:\\dir"`,
+		"a\nb\r\tc":         `"a\nb\r\tc"`,
+		"\b\f":              `"\b\f"`,
+		"\x00\x1f\x7f":      `"\u0000\u001F\u007F"`,
+		"看 😀 \u2028":        "\"看 😀 \u2028\"",
+		"bad \xff utf8":     "\"bad \uFFFD utf8\"",
+		`"){injected}#`:     `"\"){injected}#"`,
+		`\"`:                `"\\\""`,
+		"</script>&amp;":    `"</script>&amp;"`,
+		"tab\tkeeps escape": `"tab\tkeeps escape"`,
+	} {
+		assert.Equal(t, quoted, quoteString(s))
+	}
+	assert.Equal(t, `"a\"b"`, StringFromChan(argString(`a"b`).StringChan()))
+	assert.Equal(t, `["a\"b","\\"]`, StringFromChan(argStringSlice{`a"b`, `\`}.StringChan()))
+}
+
+func FuzzQuoteString(f *testing.F) {
+	for _, s := range []string{"", `"`, `\`, "\n\r\t", "\x00\x01\x7f", "\u2028\u2029", "😀", "\xff\xfe", `"""`, `\u0041`, "</script>"} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		unquoted, err := unquoteGraphQLString(quoteString(s))
+		if err != nil {
+			t.Fatalf("quoteString(%q) = %s does not parse: %v", s, quoteString(s), err)
+		}
+		// invalid UTF-8 bytes are replaced by U+FFFD one by one
+		if want := string([]rune(s)); unquoted != want {
+			t.Fatalf("quoteString(%q) = %s parses to %q", s, quoteString(s), unquoted)
+		}
+	})
+}
+
+// unquoteGraphQLString parses a GraphQL string value according to the spec.
+// See: http://facebook.github.io/graphql/October2016/#sec-String-Value
+func unquoteGraphQLString(s string) (string, error) {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return "", errors.Errorf("%s is not quoted", s)
+	}
+	var b strings.Builder
+	rs := []rune(s[1 : len(s)-1])
+	for i := 0; i < len(rs); i++ {
+		r := rs[i]
+		switch {
+		case r == '"':
+			return "", errors.Errorf("unescaped quote at %d", i)
+		case r == '\n' || r == '\r':
+			return "", errors.Errorf("line terminator at %d", i)
+		case r < 0x20 && r != '\t':
+			return "", errors.Errorf("control character %U at %d", r, i)
+		case r != '\\':
+			b.WriteRune(r)
+			continue
+		}
+		i++
+		if i == len(rs) {
+			return "", errors.New("unterminated escape sequence")
+		}
+		switch rs[i] {
+		case '"', '\\', '/':
+			b.WriteRune(rs[i])
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case 'u':
+			if i+4 >= len(rs) {
+				return "", errors.New("short unicode escape sequence")
+			}
+			v, err := strconv.ParseUint(string(rs[i+1:i+5]), 16, 16)
+			if err != nil {
+				return "", errors.WithStack(err)
+			}
+			b.WriteRune(rune(v))
+			i += 4
+		default:
+			return "", errors.Errorf("invalid escape sequence \\%c", rs[i])
+		}
+	}
+	return b.String(), nil
+}
+
+func TestArgumentList(t *testing.T) {
+	a := ArgumentList("matrix", ValueList(ValueInt(1), ValueInt(2)), ValueList(ValueInt(3), Null), ValueList())
+	assert.Equal(t, "matrix:[[1,2],[3,null],[]]", tokenString(a.tokens))
+
+	a = ArgumentList(
+		"mixed",
+		ValueBool(true), ValueFloat(1.5), ValueString("s"), ValueEnum("ASC"), ValueVariable("v"),
+		ValueObject(ArgumentList("deep", ValueList(ValueList(ValueEnum("X"))))),
+	)
+	assert.Equal(t, `mixed:[true,1.5,"s",ASC,$v,{deep:[[[X]]]}]`, tokenString(a.tokens))
+	assert.Equal(t, []string{"v"}, valueVariables(a.Value))
+	assert.Nil(t, checkValue(a.Value))
+
+	assert.IsType(t, InvalidEnumValueErr{}, errors.Cause(checkValue(argList{argList{argEnum("null")}})))
+	assert.IsType(t, NilValueErr{}, errors.Cause(checkValue(argList{argList{nil}})))
+
+	arg, err := ArgumentAny("matrix", [][]int{{1, 2}, {3}})
+	assert.Nil(t, err)
+	assert.Equal(t, Argument{"matrix", argList{argIntSlice{1, 2}, argIntSlice{3}}}, arg)
+
+	arg, err = ArgumentAny("list", []interface{}{1, "a", nil, [2]bool{true, false}, []*int{nil}})
+	assert.Nil(t, err)
+	assert.Equal(t, `list:[1,"a",null,[true,false],[null]]`, tokenString(arg.tokens))
+
+	_, err = ArgumentAny("list", [][]complex64{{1}})
+	assert.IsType(t, ArgumentTypeNotSupportedErr{}, errors.Cause(err))
+}
+
+type testMoney struct {
+	Cents int64
+}
+
+func (m testMoney) Literal() string {
+	return ValueString(fmt.Sprintf("%d.%02d", m.Cents/100, m.Cents%100)).Literal()
+}
+
+type testDecimal string
+
+func (d *testDecimal) Literal() string {
+	return string(*d)
+}
+
+func TestValue_custom(t *testing.T) {
+	a := Argument{"price", testMoney{1999}}
+	assert.Equal(t, `price:"19.99"`, tokenString(a.tokens))
+
+	a, err := ArgumentAny("price", testMoney{5})
+	assert.Nil(t, err)
+	assert.Equal(t, `price:"0.05"`, tokenString(a.tokens))
+
+	a, err = ArgumentAny("input", map[string]interface{}{"prices": []testMoney{{100}}, "none": (*testMoney)(nil)})
+	assert.Nil(t, err)
+	assert.Equal(t, `input:{none:null,prices:["1.00"]}`, tokenString(a.tokens))
+
+	// pointer receivers are used for addressable values
+	a, err = ArgumentAny("amounts", []testDecimal{"1.50"})
+	assert.Nil(t, err)
+	assert.Equal(t, `amounts:[1.50]`, tokenString(a.tokens))
+
+	q := MakeQuery(TypeMutation).SetFields(MakeField("pay").SetArguments(ArgumentList("amounts", testMoney{1}, ValueInt(2))))
+	s, err := q.JSON()
+	assert.Nil(t, err)
+	assert.Equal(t, `{"query":"mutation{pay(amounts:[\"0.01\",2])}"}`, s)
+
+	// a nil pointer is not rendered as a custom Value
+	_, err = MakeField("pay").SetArguments(Argument{"amount", (*testDecimal)(nil)}).StringChan()
+	assert.IsType(t, NilValueErr{}, errors.Cause(err))
+	_, err = MakeField("pay").SetArguments(ArgumentList("amounts", (*testDecimal)(nil))).StringChan()
+	assert.IsType(t, NilValueErr{}, errors.Cause(err))
+}
@@ -0,0 +1,86 @@
+package graphb
+
+import (
+	"io/ioutil"
+	"strconv"
+	"testing"
+)
+
+// reportQuery returns a query of about 2,000 fields in 40 objects with arguments and nested values.
+func reportQuery() *Query {
+	q := MakeQuery(TypeQuery).SetName("Report").SetVariables(Variable("from", NonNullType(NamedType("Date"))))
+	for i := 0; i < 40; i++ {
+		f := MakeField("metric"+strconv.Itoa(i)).SetArguments(
+			ArgumentVariable("from", "from"),
+			ArgumentString("label", "Metric \""+strconv.Itoa(i)+"\""),
+			ArgumentCustomType("filter", ArgumentEnum("kind", "DAILY"), ArgumentIntSlice("ids", 1, 2, 3)),
+		)
+		fields := make([]*Field, 50)
+		for j := range fields {
+			fields[j] = MakeField("value" + strconv.Itoa(j))
+		}
+		q.AddFields(f.SetFields(fields...))
+	}
+	return q
+}
+
+func BenchmarkQuery_StringChan(b *testing.B) {
+	q := reportQuery()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		strCh, err := q.StringChan()
+		if err != nil {
+			b.Fatal(err)
+		}
+		StringFromChan(strCh)
+	}
+}
+
+func BenchmarkQuery_JSON(b *testing.B) {
+	q := reportQuery()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := q.JSON(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkQuery_Render(b *testing.B) {
+	q := reportQuery()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := q.Render(ioutil.Discard); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkQuery_pretty(b *testing.B) {
+	q := reportQuery()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := q.Render(ioutil.Discard, OfPretty()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkTemplate_JSON(b *testing.B) {
+	t, err := reportQuery().Compile()
+	if err != nil {
+		b.Fatal(err)
+	}
+	variables := map[string]interface{}{"from": "2024-01-01"}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := t.JSON(variables); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
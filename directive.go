@@ -0,0 +1,153 @@
+package graphb
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Directive represents a GraphQL directive, e.g. @include(if: $flag).
+type Directive struct {
+	Name      string // Name is the directive name without the leading @.
+	Arguments []Argument
+}
+
+func (d Directive) tokens(sink tokenSink) {
+	sink(tokenAt)
+	sink(d.Name)
+	if len(d.Arguments) > 0 {
+		sink(tokenLP)
+		for i := range d.Arguments {
+			if i != 0 {
+				sink(tokenComma)
+			}
+			d.Arguments[i].tokens(sink)
+		}
+		sink(tokenRP)
+	}
+}
+
+func (d Directive) check() error {
+	if !validName.MatchString(d.Name) {
+		return errors.WithStack(InvalidNameErr{directiveName, d.Name})
+	}
+	for _, arg := range d.Arguments {
+		if !validName.MatchString(arg.Name) {
+			return errors.WithStack(InvalidNameErr{argumentName, arg.Name})
+		}
+		if err := checkValue(arg.Value); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+////////////////
+// Public API //
+////////////////
+
+// MakeDirective returns a Directive of given name and arguments.
+func MakeDirective(name string, arguments ...Argument) Directive {
+	return Directive{Name: name, Arguments: arguments}
+}
+
+// Include returns the @include(if: $varName) directive.
+func Include(varName string) Directive {
+	return MakeDirective("include", ArgumentVariable("if", varName))
+}
+
+// Skip returns the @skip(if: $varName) directive.
+func Skip(varName string) Directive {
+	return MakeDirective("skip", ArgumentVariable("if", varName))
+}
+
+//////////////////////////
+// Directive Locations //
+//////////////////////////
+
+// directiveLocation is an executable location where a directive can appear.
+type directiveLocation string
+
+const (
+	locationQuery              directiveLocation = "QUERY"
+	locationMutation           directiveLocation = "MUTATION"
+	locationSubscription       directiveLocation = "SUBSCRIPTION"
+	locationField              directiveLocation = "FIELD"
+	locationFragmentDefinition directiveLocation = "FRAGMENT_DEFINITION"
+	locationFragmentSpread     directiveLocation = "FRAGMENT_SPREAD"
+	locationInlineFragment     directiveLocation = "INLINE_FRAGMENT"
+	locationVariableDefinition directiveLocation = "VARIABLE_DEFINITION"
+)
+
+// builtinDirectiveLocations lists the executable locations of the directives defined by the spec, none of which is repeatable.
+// Directives which are only used by schemas have no executable location.
+// Other directives are defined by the server and are allowed everywhere.
+var builtinDirectiveLocations = map[string][]directiveLocation{
+	"include":     {locationField, locationFragmentSpread, locationInlineFragment},
+	"skip":        {locationField, locationFragmentSpread, locationInlineFragment},
+	"deprecated":  nil,
+	"specifiedBy": nil,
+	"oneOf":       nil,
+}
+
+// operationLocation returns the directive location of an operation of given type.
+func operationLocation(Type operationType) directiveLocation {
+	return directiveLocation(strings.ToUpper(string(Type)))
+}
+
+// checkLocation checks whether this directive is allowed on the given location.
+func (d Directive) checkLocation(location directiveLocation) error {
+	locations, ok := builtinDirectiveLocations[d.Name]
+	if !ok {
+		return nil
+	}
+	for _, l := range locations {
+		if l == location {
+			return nil
+		}
+	}
+	return errors.WithStack(InvalidDirectiveLocationErr{d.Name, location})
+}
+
+/////////////
+// Helpers //
+/////////////
+
+// directivesTokens prints the tokens of a list of directives.
+func directivesTokens(sink tokenSink, directives []Directive) {
+	for _, d := range directives {
+		d.tokens(sink)
+	}
+}
+
+// checkDirectives checks a list of directives which appear on the given location.
+// The directives defined by the spec are not repeatable, other directives may be repeated.
+func checkDirectives(directives []Directive, location directiveLocation) error {
+	seen := make(map[string]bool)
+	for _, d := range directives {
+		if err := d.check(); err != nil {
+			return errors.WithStack(err)
+		}
+		if err := d.checkLocation(location); err != nil {
+			return errors.WithStack(err)
+		}
+		if _, builtin := builtinDirectiveLocations[d.Name]; builtin {
+			if seen[d.Name] {
+				return errors.WithStack(DuplicateDirectiveErr{d.Name, location})
+			}
+			seen[d.Name] = true
+		}
+	}
+	return nil
+}
+
+// directivesVariables returns the names of all variables referenced by a list of directives in order of appearance.
+func directivesVariables(directives []Directive) []string {
+	var names []string
+	for _, d := range directives {
+		for _, arg := range d.Arguments {
+			names = append(names, valueVariables(arg.Value)...)
+		}
+	}
+	return names
+}
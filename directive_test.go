@@ -0,0 +1,145 @@
+package graphb
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDirective(t *testing.T) {
+	assert.Equal(t, "@include(if:$flag)", tokenString(Include("flag").tokens))
+	assert.Equal(t, "@skip(if:$flag)", tokenString(Skip("flag").tokens))
+	assert.Equal(t, "@live", tokenString(MakeDirective("live").tokens))
+	assert.Equal(t, `@cached(ttl:60,scope:"private")`, tokenString(MakeDirective("cached", ArgumentInt("ttl", 60), ArgumentString("scope", "private")).tokens))
+
+	assert.Nil(t, MakeDirective("cached", ArgumentInt("ttl", 60)).check())
+	assert.IsType(t, InvalidNameErr{}, errors.Cause(MakeDirective("@include").check()))
+	assert.IsType(t, InvalidNameErr{}, errors.Cause(MakeDirective("cached", ArgumentInt("t-t-l", 60)).check()))
+	assert.IsType(t, InvalidNameErr{}, errors.Cause(Include("1").check()))
+	assert.IsType(t, NilValueErr{}, errors.Cause(MakeDirective("cached", Argument{"ttl", nil}).check()))
+}
+
+func TestField_Directives(t *testing.T) {
+	t.Run("method chaining", func(t *testing.T) {
+		q := MakeQuery(TypeQuery).
+			SetVariables(Variable("withEmail", NonNullType(NamedType("Boolean")))).
+			SetFields(
+				MakeField("user").
+					SetArguments(ArgumentInt("id", 1)).
+					SetDirectives(MakeDirective("cached")).
+					AddDirectives(Skip("withEmail")).
+					SetFields(MakeField("email").AddDirectives(Include("withEmail"))),
+			)
+		strCh, err := q.StringChan()
+		assert.Nil(t, err)
+		assert.Equal(t, `query($withEmail:Boolean!){user(id:1)@cached@skip(if:$withEmail){email@include(if:$withEmail)}}`, StringFromChan(strCh))
+	})
+
+	t.Run("functional options", func(t *testing.T) {
+		f := NewField("email", OfDirective("include", ArgumentBool("if", false)))
+		assert.Nil(t, f.E)
+		strCh, err := f.StringChan()
+		assert.Nil(t, err)
+		assert.Equal(t, `email@include(if:false)`, StringFromChan(strCh))
+
+		f = NewField("email", OfDirective("in clude"))
+		assert.IsType(t, InvalidNameErr{}, errors.Cause(f.E))
+		assert.Equal(t, "'in clude' is an invalid directive name in GraphQL. A valid name matches /[_A-Za-z][_0-9A-Za-z]*/, see: http://facebook.github.io/graphql/October2016/#sec-Names", f.E.Error())
+	})
+
+	t.Run("variables used in directives must be defined", func(t *testing.T) {
+		q := MakeQuery(TypeQuery).SetFields(MakeField("email").SetDirectives(Include("withEmail")))
+		_, err := q.StringChan()
+		assert.IsType(t, UndefinedVariableErr{}, errors.Cause(err))
+	})
+
+	t.Run("invalid directive", func(t *testing.T) {
+		_, err := MakeField("email").SetDirectives(Directive{Name: ""}).StringChan()
+		assert.IsType(t, InvalidNameErr{}, errors.Cause(err))
+	})
+}
+
+func TestDirective_locations(t *testing.T) {
+	t.Run("every executable location", func(t *testing.T) {
+		user := NewFragment("UserParts", "User", OfDirective("cached", ArgumentInt("ttl", 10)), OfField("id"))
+		assert.Nil(t, user.E)
+		q := NewQuery(
+			TypeQuery,
+			OfName("Q"),
+			OfDirective("cached", ArgumentVariable("ttl", "ttl")),
+			OfVariable("ttl", NamedType("Int"), OfDefault(60), OfDirective("deprecatedVar")),
+			OfVariable("withUser", NamedType("Boolean")),
+			OfField(
+				"node",
+				OfDirective("live"),
+				OfFragmentSpread(user, OfDirective("include", ArgumentVariable("if", "withUser"))),
+				OfInlineFragment("Repo", OfDirective("skip", ArgumentVariable("if", "withUser")), OfFields("stars")),
+			),
+		)
+		assert.Nil(t, q.E)
+		strCh, err := q.StringChan()
+		assert.Nil(t, err)
+		assert.Equal(t, `query Q($ttl:Int=60@deprecatedVar,$withUser:Boolean)@cached(ttl:$ttl){node@live{...UserParts@include(if:$withUser),...on Repo@skip(if:$withUser){stars}}}fragment UserParts on User@cached(ttl:10){id}`, StringFromChan(strCh))
+	})
+
+	t.Run("method chaining", func(t *testing.T) {
+		user := MakeFragment("UserParts", "User").SetFields(MakeField("id")).AddDirectives(MakeDirective("a"))
+		q := MakeQuery(TypeMutation).
+			SetVariables(Variable("x", NamedType("Int")).SetDirectives(MakeDirective("b"))).
+			SetDirectives(MakeDirective("c")).
+			AddDirectives(MakeDirective("d", ArgumentVariable("x", "x"))).
+			SetFields(MakeField("f").SetFields(user.Spread().SetDirectives(MakeDirective("e"))))
+		strCh, err := q.StringChan()
+		assert.Nil(t, err)
+		assert.Equal(t, `mutation($x:Int@b)@c@d(x:$x){f{...UserParts@e}}fragment UserParts on User@a{id}`, StringFromChan(strCh))
+	})
+
+	t.Run("misplaced directives", func(t *testing.T) {
+		_, err := MakeQuery(TypeQuery).SetDirectives(Skip("x")).SetVariables(Variable("x", NamedType("Boolean"))).StringChan()
+		assert.IsType(t, InvalidDirectiveLocationErr{}, errors.Cause(err))
+		assert.Equal(t, "Directive '@skip' is not allowed on QUERY", err.Error())
+
+		_, err = MakeQuery(TypeSubscription).SetDirectives(Include("x")).StringChan()
+		assert.Equal(t, "Directive '@include' is not allowed on SUBSCRIPTION", err.Error())
+
+		user := MakeFragment("UserParts", "User").SetFields(MakeField("id")).SetDirectives(Include("x"))
+		_, err = user.StringChan()
+		assert.Equal(t, "Directive '@include' is not allowed on FRAGMENT_DEFINITION", err.Error())
+
+		_, err = MakeQuery(TypeQuery).SetVariables(Variable("x", NamedType("Boolean")).SetDirectives(Skip("x"))).StringChan()
+		assert.Equal(t, "Directive '@skip' is not allowed on VARIABLE_DEFINITION", err.Error())
+
+		_, err = MakeField("f").SetDirectives(MakeDirective("deprecated")).StringChan()
+		assert.Equal(t, "Directive '@deprecated' is not allowed on FIELD", err.Error())
+
+		_, err = MakeQuery(TypeQuery).SetFields(user.SetDirectives().Spread().SetDirectives(MakeDirective("specifiedBy"))).StringChan()
+		assert.Equal(t, "Directive '@specifiedBy' is not allowed on FRAGMENT_SPREAD", err.Error())
+
+		_, err = MakeInlineFragment("User").SetFields(MakeField("id")).SetDirectives(MakeDirective("oneOf")).StringChan()
+		assert.Equal(t, "Directive '@oneOf' is not allowed on INLINE_FRAGMENT", err.Error())
+	})
+
+	t.Run("repeated directives", func(t *testing.T) {
+		_, err := MakeField("f").SetDirectives(Include("a"), Include("a")).StringChan()
+		assert.IsType(t, DuplicateDirectiveErr{}, errors.Cause(err))
+		assert.Equal(t, "Directive '@include' is not repeatable but appears more than once on FIELD", err.Error())
+
+		_, err = MakeInlineFragment("").SetFields(MakeField("id")).SetDirectives(Skip("a"), Include("b"), Skip("c")).StringChan()
+		assert.Equal(t, DuplicateDirectiveErr{"skip", locationInlineFragment}, errors.Cause(err))
+
+		// directives defined by the server may be repeatable
+		strCh, err := MakeField("f").SetDirectives(MakeDirective("tag", ArgumentString("name", "a")), MakeDirective("tag", ArgumentString("name", "b"))).StringChan()
+		assert.Nil(t, err)
+		assert.Equal(t, `f@tag(name:"a")@tag(name:"b")`, StringFromChan(strCh))
+	})
+
+	t.Run("variables used in operation and fragment directives must be defined", func(t *testing.T) {
+		_, err := MakeQuery(TypeQuery).SetDirectives(MakeDirective("cached", ArgumentVariable("ttl", "ttl"))).StringChan()
+		assert.IsType(t, UndefinedVariableErr{}, errors.Cause(err))
+
+		user := MakeFragment("UserParts", "User").SetFields(MakeField("id")).SetDirectives(MakeDirective("a", ArgumentVariable("x", "x")))
+		_, err = MakeQuery(TypeQuery).SetFields(user.Spread()).StringChan()
+		assert.IsType(t, UndefinedVariableErr{}, errors.Cause(err))
+	})
+}
@@ -0,0 +1,232 @@
+package graphb
+
+import (
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Document represents a GraphQL document of several operations and fragment definitions,
+// e.g. the content of a .graphql file or a batch of persisted queries.
+// Fragments spread by the operations are rendered whether they are listed in Fragments or not.
+// Fragments lists additional definitions to render first, in order, each of which must be spread by an operation.
+type Document struct {
+	Operations []*Query
+	Fragments  []*Fragment
+	E          error
+}
+
+// StringChan returns read only string token channel of the document or an error.
+// Options such as OfBlockStrings configure the rendering.
+func (d *Document) StringChan(options ...RenderOption) (<-chan string, error) {
+	if err := d.check(); err != nil {
+		ch := make(chan string)
+		close(ch)
+		return ch, errors.WithStack(err)
+	}
+	o := newRenderOptions(options)
+	return tokenChan(func(sink tokenSink) {
+		d.render(o, sink)
+	}), nil
+}
+
+// render prints this valid Document with the options applied.
+func (d *Document) render(o renderOptions, sink tokenSink) {
+	if o.typename != typenameNone {
+		t := newTypenameInjector(o.typename)
+		c := Document{Operations: make([]*Query, len(d.Operations)), Fragments: make([]*Fragment, len(d.Fragments))}
+		for i, q := range d.Operations {
+			c.Operations[i] = t.query(q)
+		}
+		for i, fr := range d.Fragments {
+			c.Fragments[i] = t.fragment(fr)
+		}
+		d = &c
+	}
+	node := d.tokens
+	if o.shorthand && len(d.Operations) == 1 && d.Operations[0].isShorthand() {
+		node = func(sink tokenSink) {
+			d.tokens(skipFirst(sink)) // the operation type
+		}
+	}
+	o.print(node, false, sink)
+}
+
+func (d *Document) tokens(sink tokenSink) {
+	for _, q := range d.Operations {
+		q.operationTokens(sink)
+	}
+	// the fragments have been validated by check
+	fragments, _ := d.fragments()
+	for _, fr := range fragments {
+		fr.tokens(sink)
+	}
+}
+
+// fragments returns d.Fragments followed by all other fragments spread by the operations, in order of first appearance.
+func (d *Document) fragments() ([]*Fragment, error) {
+	fragments := append([]*Fragment{}, d.Fragments...)
+	byName := make(map[string]*Fragment)
+	for _, fr := range d.Fragments {
+		if byName[fr.Name] != nil {
+			return nil, errors.WithStack(DuplicateFragmentErr{fr.Name})
+		}
+		byName[fr.Name] = fr
+	}
+	used := make(map[*Fragment]bool)
+	for _, q := range d.Operations {
+		spread, err := collectFragments(q.Fields)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		for _, fr := range spread {
+			used[fr] = true
+			if seen, ok := byName[fr.Name]; ok {
+				if seen != fr {
+					return nil, errors.WithStack(DuplicateFragmentErr{fr.Name})
+				}
+				continue
+			}
+			byName[fr.Name] = fr
+			fragments = append(fragments, fr)
+		}
+	}
+	for _, fr := range d.Fragments {
+		if !used[fr] {
+			return nil, errors.WithStack(UnusedFragmentErr{fr.Name})
+		}
+	}
+	return fragments, nil
+}
+
+func (d *Document) check() error {
+	if d.E != nil {
+		return errors.WithStack(d.E)
+	}
+	if len(d.Operations) == 0 {
+		return errors.WithStack(EmptyDocumentErr{})
+	}
+	names := make(map[string]bool, len(d.Operations))
+	for _, q := range d.Operations {
+		if q == nil {
+			return errors.WithStack(NilDefinitionErr{})
+		}
+		if q.Name == "" && len(d.Operations) > 1 {
+			return errors.WithStack(AnonymousOperationErr{})
+		}
+		if names[q.Name] {
+			return errors.WithStack(DuplicateOperationErr{q.Name})
+		}
+		names[q.Name] = true
+		if err := q.check(); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	for _, fr := range d.Fragments {
+		if fr == nil {
+			return errors.WithStack(NilDefinitionErr{})
+		}
+		if err := fr.check(); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	if _, err := d.fragments(); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+////////////////
+// Public API //
+////////////////
+
+// MakeDocument constructs a Document of the given operations and returns a pointer of it.
+func MakeDocument(operations ...*Query) *Document {
+	return &Document{Operations: operations}
+}
+
+// AddOperations adds to the operations of this Document.
+func (d *Document) AddOperations(operations ...*Query) *Document {
+	d.Operations = append(d.Operations, operations...)
+	return d
+}
+
+// AddFragments adds to the fragment definitions of this Document.
+func (d *Document) AddFragments(fragments ...*Fragment) *Document {
+	d.Fragments = append(d.Fragments, fragments...)
+	return d
+}
+
+// GetOperation returns the operation identified by the name. Nil if not exist.
+func (d *Document) GetOperation(name string) *Query {
+	for _, q := range d.Operations {
+		if q != nil && q.Name == name {
+			return q
+		}
+	}
+	return nil
+}
+
+// JSON returns a json string with "query" and "operationName" fields, where operationName selects the operation to execute.
+// operationName may be empty only when the document contains a single operation, in which case the field is omitted.
+// Options such as OfBlockStrings configure the rendering of the document.
+func (d *Document) JSON(operationName string, options ...RenderOption) (string, error) {
+	var b strings.Builder
+	if _, err := d.WriteJSON(&b, operationName, options...); err != nil {
+		return "", errors.WithStack(err)
+	}
+	return b.String(), nil
+}
+
+// WriteJSON writes the json request body with "query" and "operationName" fields to w, see JSON.
+// It returns the number of bytes written. Nothing is written if the document or the operation name is invalid.
+func (d *Document) WriteJSON(w io.Writer, operationName string, options ...RenderOption) (int64, error) {
+	if err := d.checkRequest(operationName); err != nil {
+		return 0, errors.WithStack(err)
+	}
+	o := newRenderOptions(options)
+	return writeJSON(w, func(sink tokenSink) {
+		d.render(o, sink)
+	}, operationName)
+}
+
+// JSONReader returns a reader of the json request body with "query" and "operationName" fields, see JSON.
+// The document is rendered when JSONReader is called.
+func (d *Document) JSONReader(operationName string, options ...RenderOption) (io.Reader, error) {
+	if err := d.checkRequest(operationName); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	o := newRenderOptions(options)
+	r, err := jsonReader(func(sink tokenSink) {
+		d.render(o, sink)
+	}, operationName)
+	return r, errors.WithStack(err)
+}
+
+// checkRequest checks the document and that the operation name selects one of its operations.
+func (d *Document) checkRequest(operationName string) error {
+	if err := d.check(); err != nil {
+		return errors.WithStack(err)
+	}
+	if operationName == "" && len(d.Operations) > 1 || operationName != "" && d.GetOperation(operationName) == nil {
+		return errors.WithStack(UnknownOperationErr{operationName})
+	}
+	return nil
+}
+
+// Render writes the document to w and returns the number of bytes written. Nothing is written if the document is invalid.
+func (d *Document) Render(w io.Writer, options ...RenderOption) (int64, error) {
+	if err := d.check(); err != nil {
+		return 0, errors.WithStack(err)
+	}
+	o := newRenderOptions(options)
+	return writeTokens(w, func(sink tokenSink) {
+		d.render(o, sink)
+	})
+}
+
+// WriteTo implements io.WriterTo. It writes the minified document like Render without options.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	return d.Render(w)
+}
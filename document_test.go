@@ -0,0 +1,83 @@
+package graphb
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocument(t *testing.T) {
+	userParts := MakeFragment("UserParts", "User").SetFields(Fields("id", "name")...)
+	avatar := MakeFragment("Avatar", "User").SetFields(MakeField("avatar"))
+	getUser := MakeQuery(TypeQuery).
+		SetName("GetUser").
+		SetVariables(Variable("id", NonNullType(NamedType("ID")))).
+		SetFields(MakeField("user").SetArguments(ArgumentVariable("id", "id")).SetFields(userParts.Spread(), avatar.Spread()))
+	me := MakeQuery(TypeQuery).SetName("Me").SetFields(MakeField("me").SetFields(userParts.Spread()))
+
+	d := MakeDocument(getUser, me).AddFragments(avatar)
+	strCh, err := d.StringChan()
+	assert.Nil(t, err)
+	assert.Equal(t, "query GetUser($id:ID!){user(id:$id){...UserParts,...Avatar}}query Me{me{...UserParts}}"+
+		"fragment Avatar on User{avatar}fragment UserParts on User{id,name}", StringFromChan(strCh))
+
+	s, err := d.JSON("Me")
+	assert.Nil(t, err)
+	assert.Equal(t, `{"query":"query GetUser($id:ID!){user(id:$id){...UserParts,...Avatar}}query Me{me{...UserParts}}`+
+		`fragment Avatar on User{avatar}fragment UserParts on User{id,name}","operationName":"Me"}`, s)
+
+	_, err = d.JSON("")
+	assert.IsType(t, UnknownOperationErr{}, errors.Cause(err))
+	_, err = d.JSON("Other")
+	assert.Equal(t, "Operation 'Other' is not defined by the Document", err.Error())
+
+	d = NewDocument(
+		OfOperation(TypeQuery, OfName("GetUser"), OfVariable("id", NonNullType(NamedType("ID"))),
+			OfField("user", OfArguments(ArgumentVariable("id", "id")), OfFragmentSpread(userParts), OfFragmentSpread(avatar))),
+		OfOperation(TypeQuery, OfName("Me"), OfField("me", OfFragmentSpread(userParts))),
+		OfFragmentDefinitions(avatar),
+	)
+	assert.Nil(t, d.E)
+	strCh, err = d.StringChan()
+	assert.Nil(t, err)
+	assert.Equal(t, "query GetUser($id:ID!){user(id:$id){...UserParts,...Avatar}}query Me{me{...UserParts}}"+
+		"fragment Avatar on User{avatar}fragment UserParts on User{id,name}", StringFromChan(strCh))
+
+	s, err = (&Document{Operations: []*Query{MakeQuery(TypeQuery).SetFields(MakeField("x"))}}).JSON("")
+	assert.Nil(t, err)
+	assert.Equal(t, `{"query":"query{x}"}`, s)
+}
+
+func TestDocument_check(t *testing.T) {
+	named := func(name string) *Query {
+		return MakeQuery(TypeQuery).SetName(name).SetFields(MakeField("x"))
+	}
+	fr := MakeFragment("F", "T").SetFields(MakeField("x"))
+
+	cases := []struct {
+		name string
+		doc  *Document
+		err  error
+	}{
+		{"empty", MakeDocument(), EmptyDocumentErr{}},
+		{"nil operation", MakeDocument(named("A"), nil), NilDefinitionErr{}},
+		{"nil fragment", MakeDocument(named("A")).AddFragments(nil), NilDefinitionErr{}},
+		{"anonymous operation", MakeDocument(named("A"), named("")), AnonymousOperationErr{}},
+		{"duplicate operation", MakeDocument(named("A"), named("A")), DuplicateOperationErr{"A"}},
+		{"invalid operation", MakeDocument(named("A"), named("1")), InvalidNameErr{operationName, "1"}},
+		{"unused fragment", MakeDocument(named("A")).AddFragments(fr), UnusedFragmentErr{"F"}},
+		{"duplicate fragment", MakeDocument(named("A").SetFields(fr.Spread()), named("B").SetFields(MakeFragment("F", "T").SetFields(MakeField("y")).Spread())), DuplicateFragmentErr{"F"}},
+		{"invalid operation option", NewDocument(OfOperation(TypeQuery, OfName("1"))), InvalidNameErr{operationName, "1"}},
+		{"invalid fragment option", NewDocument(OfFragmentDefinitions(NewFragment("F", "T", OfField("x", OfAlias("1"))))), InvalidNameErr{aliasName, "1"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := c.doc.StringChan()
+			assert.Equal(t, c.err, errors.Cause(err))
+		})
+	}
+
+	assert.Nil(t, MakeDocument(named("")).check())
+	assert.Nil(t, MakeDocument(named("A").SetFields(fr.Spread()), named("B").SetFields(fr.Spread())).AddFragments(fr).check())
+}
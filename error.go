@@ -2,6 +2,7 @@ package graphb
 
 import (
 	"fmt"
+	"strings"
 )
 
 type nameType string
@@ -11,9 +12,13 @@ const (
 	aliasName     nameType = "alias name"
 	fieldName     nameType = "field name"
 	argumentName  nameType = "argument name"
+	variableName  nameType = "variable name"
+	typeName      nameType = "type name"
+	fragmentName  nameType = "fragment name"
+	directiveName nameType = "directive name"
 )
 
-// InvalidNameErr is returned when an invalid name is used. In GraphQL, operation, alias, field and argument all have names.
+// InvalidNameErr is returned when an invalid name is used. In GraphQL, operation, alias, field, argument, variable, type, fragment and directive all have names.
 // A valid name matches ^[_A-Za-z][_0-9A-Za-z]*$ exactly.
 type InvalidNameErr struct {
 	Type nameType
@@ -33,6 +38,35 @@ func (e InvalidOperationTypeErr) Error() string {
 	return fmt.Sprintf("'%s' is an invalid operation type in GraphQL. A valid type is one of 'query', 'mutation', 'subscription'", e.Type)
 }
 
+// DuplicateVariableErr is returned when an operation defines more than one variable of the same name.
+type DuplicateVariableErr struct {
+	Name string
+}
+
+func (e DuplicateVariableErr) Error() string {
+	return fmt.Sprintf("Variable '$%s' is defined more than once. Variables of an operation must be uniquely named", e.Name)
+}
+
+// UndefinedVariableErr is returned when a variable is referenced but not defined by the operation.
+type UndefinedVariableErr struct {
+	Name string
+}
+
+func (e UndefinedVariableErr) Error() string {
+	return fmt.Sprintf("Variable '$%s' is not defined by the operation", e.Name)
+}
+
+// NonConstantDefaultErr is returned when the default value of a variable refers to a variable.
+// See: https://spec.graphql.org/October2021/#sec-Variables
+type NonConstantDefaultErr struct {
+	Name     string // Name is the name of the variable whose default value refers to Variable.
+	Variable string
+}
+
+func (e NonConstantDefaultErr) Error() string {
+	return fmt.Sprintf("Default value of variable '$%s' refers to variable '$%s'. Default values must be constant", e.Name, e.Variable)
+}
+
 // NilFieldErr is returned when any field is nil. Of course the author could choose to ignore nil fields. But, author chose a stricter construct.
 type NilFieldErr struct{}
 
@@ -40,6 +74,14 @@ func (e NilFieldErr) Error() string {
 	return "nil Field is not allowed. Please initialize a correct Field with NewField(...) function or Field{...} literal"
 }
 
+// NilValueErr is returned when the value of an argument, of a field of a custom type or an item of a list value is nil,
+// or is a nil pointer. Use Null for the null value.
+type NilValueErr struct{}
+
+func (e NilValueErr) Error() string {
+	return "nil value is not allowed. Please use Null for the null value"
+}
+
 // CyclicFieldErr is returned when any field contains a loop which goes back to itself.
 type CyclicFieldErr struct {
 	Field Field
@@ -49,11 +91,206 @@ func (e CyclicFieldErr) Error() string {
 	return fmt.Sprintf("Field %+v contains cyclic loop", e.Field)
 }
 
+// CyclicFragmentErr is returned when a fragment spreads itself, directly or indirectly.
+type CyclicFragmentErr struct {
+	Name string
+}
+
+func (e CyclicFragmentErr) Error() string {
+	return fmt.Sprintf("Fragment '%s' spreads itself", e.Name)
+}
+
+// DuplicateFragmentErr is returned when different fragments of the same name are used together.
+type DuplicateFragmentErr struct {
+	Name string
+}
+
+func (e DuplicateFragmentErr) Error() string {
+	return fmt.Sprintf("Fragment '%s' is defined more than once. Fragments must be uniquely named", e.Name)
+}
+
+// EmptyFragmentErr is returned when a fragment definition has no fields.
+type EmptyFragmentErr struct {
+	Name string
+}
+
+func (e EmptyFragmentErr) Error() string {
+	return fmt.Sprintf("Fragment '%s' must have sub fields", e.Name)
+}
+
+// InvalidFragmentSpreadErr is returned when a fragment spread Field also has a name, alias, arguments or sub fields,
+// or is also an inline fragment.
+type InvalidFragmentSpreadErr struct {
+	Name string
+}
+
+func (e InvalidFragmentSpreadErr) Error() string {
+	return fmt.Sprintf("Fragment spread '...%s' must not have a name, alias, arguments or sub fields", e.Name)
+}
+
+// InvalidInlineFragmentErr is returned when an inline fragment Field has a name, alias or arguments, or has no sub fields.
+type InvalidInlineFragmentErr struct {
+	On string
+}
+
+func (e InvalidInlineFragmentErr) Error() string {
+	return fmt.Sprintf("Inline fragment on '%s' must not have a name, alias or arguments and must have sub fields", e.On)
+}
+
+// InvalidDirectiveLocationErr is returned when a directive is used on a location where GraphQL does not allow it,
+// e.g. @include on an operation.
+type InvalidDirectiveLocationErr struct {
+	Name     string
+	Location directiveLocation
+}
+
+func (e InvalidDirectiveLocationErr) Error() string {
+	return fmt.Sprintf("Directive '@%s' is not allowed on %s", e.Name, e.Location)
+}
+
+// DuplicateDirectiveErr is returned when a directive which is not repeatable appears more than once on a location.
+// See: https://spec.graphql.org/October2021/#sec-Directives-Are-Unique-Per-Location
+type DuplicateDirectiveErr struct {
+	Name     string
+	Location directiveLocation
+}
+
+func (e DuplicateDirectiveErr) Error() string {
+	return fmt.Sprintf("Directive '@%s' is not repeatable but appears more than once on %s", e.Name, e.Location)
+}
+
+// InvalidEnumValueErr is returned when an enum value is not a valid name or is one of true, false and null.
+type InvalidEnumValueErr struct {
+	Value string
+}
+
+func (e InvalidEnumValueErr) Error() string {
+	return fmt.Sprintf("'%s' is an invalid enum value in GraphQL. A valid enum value is a name but not true, false or null", e.Value)
+}
+
+// InvalidFloatErr is returned when a float argument is NaN or infinity, which GraphQL can not represent.
+type InvalidFloatErr struct {
+	Value float64
+}
+
+func (e InvalidFloatErr) Error() string {
+	return fmt.Sprintf("Float %v is not a valid GraphQL value", e.Value)
+}
+
 // ArgumentTypeNotSupportedErr is returned when user tries to pass an unsupported type to ArgumentAny.
+// Path is the path to the unsupported value inside of the argument, e.g. input.items[2].price, and empty for the argument itself.
 type ArgumentTypeNotSupportedErr struct {
 	Value interface{}
+	Path  string
 }
 
 func (e ArgumentTypeNotSupportedErr) Error() string {
+	if e.Path != "" {
+		return fmt.Sprintf("Argument %+v of Type %T at %s is not supported", e.Value, e.Value, e.Path)
+	}
 	return fmt.Sprintf("Argument %+v of Type %T is not supported", e.Value, e.Value)
 }
+
+// CyclicValueErr is returned when a value passed to ArgumentAny refers to itself.
+type CyclicValueErr struct {
+	Path string
+}
+
+func (e CyclicValueErr) Error() string {
+	return fmt.Sprintf("Argument value at %s refers to itself", e.Path)
+}
+
+// MarshalValueErr is returned when the MarshalJSON or MarshalText method of a value passed to ArgumentAny fails.
+type MarshalValueErr struct {
+	Path string
+	Err  error
+}
+
+func (e MarshalValueErr) Error() string {
+	return fmt.Sprintf("Argument value at %s could not be marshaled: %v", e.Path, e.Err)
+}
+
+// EmptyDocumentErr is returned when a Document contains no operation.
+type EmptyDocumentErr struct{}
+
+func (e EmptyDocumentErr) Error() string {
+	return "A Document must contain at least one operation"
+}
+
+// NilDefinitionErr is returned when an operation or a fragment definition of a Document, or a spread fragment, is nil.
+type NilDefinitionErr struct{}
+
+func (e NilDefinitionErr) Error() string {
+	return "nil operation or fragment definition is not allowed"
+}
+
+// AnonymousOperationErr is returned when a Document contains an anonymous operation besides other operations.
+// See: https://spec.graphql.org/October2021/#sec-Lone-Anonymous-Operation
+type AnonymousOperationErr struct{}
+
+func (e AnonymousOperationErr) Error() string {
+	return "An anonymous operation must be the only operation of a Document"
+}
+
+// DuplicateOperationErr is returned when operations of a Document share a name.
+// See: https://spec.graphql.org/October2021/#sec-Operation-Name-Uniqueness
+type DuplicateOperationErr struct {
+	Name string
+}
+
+func (e DuplicateOperationErr) Error() string {
+	return fmt.Sprintf("Operation '%s' is defined more than once. Operations of a Document must be uniquely named", e.Name)
+}
+
+// UnusedFragmentErr is returned when a fragment definition of a Document is not spread by any operation.
+// See: https://spec.graphql.org/October2021/#sec-Fragments-Must-Be-Used
+type UnusedFragmentErr struct {
+	Name string
+}
+
+func (e UnusedFragmentErr) Error() string {
+	return fmt.Sprintf("Fragment '%s' is never spread. Fragments of a Document must be used", e.Name)
+}
+
+// UnknownOperationErr is returned when the operation name of a request does not select an operation of the Document.
+type UnknownOperationErr struct {
+	Name string
+}
+
+func (e UnknownOperationErr) Error() string {
+	if e.Name == "" {
+		return "An operation name is required to select one of the operations of the Document"
+	}
+	return fmt.Sprintf("Operation '%s' is not defined by the Document", e.Name)
+}
+
+// InvalidSubscriptionErr is returned when a subscription does not select a single root field,
+// or selects an introspection root field such as __typename.
+// See: https://spec.graphql.org/October2021/#sec-Single-root-field
+type InvalidSubscriptionErr struct {
+	Name   string   // Name is the operation name.
+	Fields []string // Fields are the response keys of the root fields, or the name of the introspection field.
+}
+
+func (e InvalidSubscriptionErr) Error() string {
+	operation := "Anonymous Subscription"
+	if e.Name != "" {
+		operation = fmt.Sprintf("Subscription '%s'", e.Name)
+	}
+	switch len(e.Fields) {
+	case 0:
+		return fmt.Sprintf("%s must select one top level field", operation)
+	case 1:
+		return fmt.Sprintf("%s must not select an introspection top level field '%s'", operation, e.Fields[0])
+	}
+	return fmt.Sprintf("%s must select only one top level field, but selects %d: %s", operation, len(e.Fields), strings.Join(e.Fields, ", "))
+}
+
+// MissingVariableErr is returned when no value, or a nil value, is given for a variable of a non-null type without default value.
+type MissingVariableErr struct {
+	Name string
+}
+
+func (e MissingVariableErr) Error() string {
+	return fmt.Sprintf("Variable '$%s' of a non-null type requires a value", e.Name)
+}
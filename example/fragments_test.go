@@ -0,0 +1,65 @@
+package example
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/udacity/graphb"
+)
+
+// searchQuery is built by all three inline fragment examples below.
+// Inline fragments select fields depending on the concrete type of a union or an interface.
+const searchQuery = `{"query":"query{search(text:\"graphb\"){__typename,...on User{name},...on Repo{stars}}}"}`
+
+func TestInlineFragmentMethodChaining(t *testing.T) {
+	q := graphb.MakeQuery(graphb.TypeQuery).
+		SetFields(
+			graphb.MakeField("search").
+				SetArguments(graphb.ArgumentString("text", "graphb")).
+				SetFields(
+					graphb.MakeField("__typename"),
+					graphb.MakeInlineFragment("User").SetFields(graphb.MakeField("name")),
+					graphb.MakeInlineFragment("Repo").SetFields(graphb.MakeField("stars")),
+				),
+		)
+	s, err := q.JSON()
+	assert.Nil(t, err)
+	assert.Equal(t, searchQuery, s)
+}
+
+func TestInlineFragmentFunctionalOptions(t *testing.T) {
+	q := graphb.NewQuery(
+		graphb.TypeQuery,
+		graphb.OfField(
+			"search",
+			graphb.OfArguments(graphb.ArgumentString("text", "graphb")),
+			graphb.OfFields("__typename"),
+			graphb.OfInlineFragment("User", graphb.OfFields("name")),
+			graphb.OfInlineFragment("Repo", graphb.OfFields("stars")),
+		),
+	)
+	assert.Nil(t, q.E)
+	s, err := q.JSON()
+	assert.Nil(t, err)
+	assert.Equal(t, searchQuery, s)
+}
+
+func TestInlineFragmentStructLiteral(t *testing.T) {
+	q := graphb.Query{
+		Type: graphb.TypeQuery,
+		Fields: []*graphb.Field{
+			{
+				Name:      "search",
+				Arguments: []graphb.Argument{graphb.ArgumentString("text", "graphb")},
+				Fields: []*graphb.Field{
+					{Name: "__typename"},
+					{Inline: true, On: "User", Fields: graphb.Fields("name")},
+					{Inline: true, On: "Repo", Fields: graphb.Fields("stars")},
+				},
+			},
+		},
+	}
+	s, err := q.JSON()
+	assert.Nil(t, err)
+	assert.Equal(t, searchQuery, s)
+}
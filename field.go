@@ -1,16 +1,27 @@
 package graphb
 
 import (
+	"io"
+
 	"github.com/pkg/errors"
 )
 
 // Field is a recursive data struct which represents a GraphQL query field.
 type Field struct {
-	Name      string
-	Alias     string
-	Arguments []Argument
-	Fields    []*Field
-	E         error
+	Name       string
+	Alias      string
+	Arguments  []Argument
+	Directives []Directive
+	Fields     []*Field
+	E          error
+
+	// Fragment makes this Field a spread of the fragment, e.g. ...UserParts.
+	// A spread has no name, alias, arguments or sub fields. See Fragment.Spread.
+	Fragment *Fragment
+	// Inline makes this Field an inline fragment whose selection set is Fields, e.g. ... on User { name }.
+	// An inline fragment has no name, alias or arguments. See MakeInlineFragment.
+	Inline bool
+	On     string // On is the optional type condition of an inline fragment.
 }
 
 // Implement fieldContainer
@@ -22,9 +33,18 @@ func (f *Field) setFields(fs []*Field) {
 	f.Fields = fs
 }
 
+// Implement directiveContainer
+func (f *Field) getDirectives() []Directive {
+	return f.Directives
+}
+
+func (f *Field) setDirectives(ds []Directive) {
+	f.Directives = ds
+}
+
 // StringChan returns read only string token channel or an error.
-// It checks if there is a circle.
-func (f *Field) StringChan() (<-chan string, error) {
+// It checks if there is a circle. Options such as OfBlockStrings configure the rendering.
+func (f *Field) StringChan(options ...RenderOption) (<-chan string, error) {
 	// todo: new style error handling
 	if err := f.check(); err != nil {
 		// return a closed channel instead of nil for receiving from nil blocks forever, hard to debug and confusing to users.
@@ -32,56 +52,90 @@ func (f *Field) StringChan() (<-chan string, error) {
 		close(ch)
 		return ch, errors.WithStack(err)
 	}
-	return f.stringChan(), nil
+	o := newRenderOptions(options)
+	return tokenChan(func(sink tokenSink) {
+		f.render(o, sink)
+	}), nil
+}
+
+// Render writes the field to w and returns the number of bytes written. Nothing is written if the field is invalid.
+func (f *Field) Render(w io.Writer, options ...RenderOption) (int64, error) {
+	if err := f.check(); err != nil {
+		return 0, errors.WithStack(err)
+	}
+	o := newRenderOptions(options)
+	return writeTokens(w, func(sink tokenSink) {
+		f.render(o, sink)
+	})
 }
 
-// One may have noticed that there is a public StringChan and a private stringChan.
+// WriteTo implements io.WriterTo. It writes the minified field like Render without options.
+func (f *Field) WriteTo(w io.Writer) (int64, error) {
+	return f.Render(w)
+}
+
+// render prints this valid Field with the options applied.
+func (f *Field) render(o renderOptions, sink tokenSink) {
+	o.print(newTypenameInjector(o.typename).field(f).tokens, true, sink)
+}
+
+// One may have noticed that there is a public StringChan and a private tokens.
 // The different being the public method checks the validity of the Field structure
 // while the private counterpart assumes the validity.
-func (f *Field) stringChan() <-chan string {
-
-	tokenChan := make(chan string)
+func (f *Field) tokens(sink tokenSink) {
+	// emit fragment spread
+	if f.Fragment != nil {
+		sink(tokenSpread)
+		sink(f.Fragment.Name)
+		directivesTokens(sink, f.Directives)
+		return
+	}
 
-	go func() {
-		// emit alias and names
-		if f.Alias != "" {
-			tokenChan <- f.Alias
-			tokenChan <- tokenColumn
+	// emit inline fragment
+	if f.Inline {
+		sink(tokenSpread)
+		if f.On != "" {
+			sink("on")
+			sink(tokenSpace)
+			sink(f.On)
 		}
-		tokenChan <- f.Name
+	}
 
-		// emit argument tokens
-		if len(f.Arguments) > 0 {
-			tokenChan <- tokenLP
-			for i := range f.Arguments {
-				if i != 0 {
-					tokenChan <- tokenComma
-				}
-				for str := range f.Arguments[i].stringChan() {
-					tokenChan <- str
-				}
+	// emit alias and names
+	if f.Alias != "" {
+		sink(f.Alias)
+		sink(tokenColumn)
+	}
+	sink(f.Name)
+
+	// emit argument tokens
+	if len(f.Arguments) > 0 {
+		sink(tokenLP)
+		for i := range f.Arguments {
+			if i != 0 {
+				sink(tokenComma)
 			}
-			tokenChan <- tokenRP
+			f.Arguments[i].tokens(sink)
 		}
+		sink(tokenRP)
+	}
+
+	// emit directive tokens
+	directivesTokens(sink, f.Directives)
 
-		// emit field tokens
-		if len(f.Fields) > 0 {
-			tokenChan <- tokenLB
-			for i, field := range f.Fields {
-				if field != nil {
-					if i != 0 {
-						tokenChan <- tokenComma
-					}
-					for str := range field.stringChan() {
-						tokenChan <- str
-					}
+	// emit field tokens
+	if len(f.Fields) > 0 {
+		sink(tokenLB)
+		for i, field := range f.Fields {
+			if field != nil {
+				if i != 0 {
+					sink(tokenComma)
 				}
+				field.tokens(sink)
 			}
-			tokenChan <- tokenRB
 		}
-		close(tokenChan)
-	}()
-	return tokenChan
+		sink(tokenRB)
+	}
 }
 
 func (f *Field) check() error {
@@ -96,8 +150,19 @@ func (f *Field) check() error {
 
 // checkOther checks the validity of this Field and returns nil on valid Field.
 func (f *Field) checkOther() error {
+	if f.Fragment != nil {
+		if err := f.checkSpread(); err != nil {
+			return errors.WithStack(err)
+		}
+		return errors.WithStack(checkDirectives(f.Directives, locationFragmentSpread))
+	}
+
 	// Check validity of names
-	if !validName.MatchString(f.Name) {
+	if f.Inline {
+		if err := f.checkInline(); err != nil {
+			return errors.WithStack(err)
+		}
+	} else if !validName.MatchString(f.Name) {
 		return errors.WithStack(InvalidNameErr{fieldName, f.Name})
 	}
 	if err := f.checkAlias(); err != nil {
@@ -107,6 +172,16 @@ func (f *Field) checkOther() error {
 		if !validName.MatchString(arg.Name) {
 			return errors.WithStack(InvalidNameErr{argumentName, arg.Name})
 		}
+		if err := checkValue(arg.Value); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	location := locationField
+	if f.Inline {
+		location = locationInlineFragment
+	}
+	if err := checkDirectives(f.Directives, location); err != nil {
+		return errors.WithStack(err)
 	}
 
 	// Check sub fields
@@ -118,6 +193,26 @@ func (f *Field) checkOther() error {
 	return nil
 }
 
+func (f *Field) checkSpread() error {
+	if f.Name != "" || f.Alias != "" || len(f.Arguments) > 0 || len(f.Fields) > 0 || f.Inline {
+		return errors.WithStack(InvalidFragmentSpreadErr{f.Fragment.Name})
+	}
+	if !validName.MatchString(f.Fragment.Name) || f.Fragment.Name == "on" {
+		return errors.WithStack(InvalidNameErr{fragmentName, f.Fragment.Name})
+	}
+	return nil
+}
+
+func (f *Field) checkInline() error {
+	if f.Name != "" || f.Alias != "" || len(f.Arguments) > 0 || len(f.Fields) == 0 {
+		return errors.WithStack(InvalidInlineFragmentErr{f.On})
+	}
+	if f.On != "" && !validName.MatchString(f.On) {
+		return errors.WithStack(InvalidNameErr{typeName, f.On})
+	}
+	return nil
+}
+
 func (f *Field) checkAlias() error {
 	if f.Alias != "" && !validName.MatchString(f.Alias) {
 		return errors.WithStack(InvalidNameErr{aliasName, f.Alias})
@@ -125,6 +220,26 @@ func (f *Field) checkAlias() error {
 	return nil
 }
 
+// checkVariables checks that every variable referenced by this Field and its sub fields is defined.
+func (f *Field) checkVariables(defined map[string]bool) error {
+	var names []string
+	for _, arg := range f.Arguments {
+		names = append(names, valueVariables(arg.Value)...)
+	}
+	names = append(names, directivesVariables(f.Directives)...)
+	for _, name := range names {
+		if !defined[name] {
+			return errors.WithStack(UndefinedVariableErr{name})
+		}
+	}
+	for _, subF := range f.Fields {
+		if err := subF.checkVariables(defined); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
 // todo: reports the cycle path
 func (f *Field) checkCycle() error {
 	if err := reach(f, f); err != nil {
@@ -142,6 +257,12 @@ func MakeField(name string) *Field {
 	return &Field{Name: name}
 }
 
+// MakeInlineFragment constructs an inline fragment of given type condition and returns the pointer to it.
+// The type condition is optional and can be empty. Use SetFields to set the selection set of the inline fragment.
+func MakeInlineFragment(on string) *Field {
+	return &Field{Inline: true, On: on}
+}
+
 // SetArguments sets the arguments of a Field and return the pointer to this Field.
 func (f *Field) SetArguments(arguments ...Argument) *Field {
 	f.Arguments = arguments
@@ -153,6 +274,18 @@ func (f *Field) AddArguments(argument ...Argument) *Field {
 	return f
 }
 
+// SetDirectives sets the directives of a Field and return the pointer to this Field.
+func (f *Field) SetDirectives(directives ...Directive) *Field {
+	f.Directives = directives
+	return f
+}
+
+// AddDirectives adds to the directives of a Field and return the pointer to this Field.
+func (f *Field) AddDirectives(directives ...Directive) *Field {
+	f.Directives = append(f.Directives, directives...)
+	return f
+}
+
 // SetFields sets the sub fields of a Field and return the pointer to this Field.
 func (f *Field) SetFields(fs ...*Field) *Field {
 	f.Fields = fs
@@ -183,3 +316,27 @@ func reach(f1, f2 *Field) error {
 	}
 	return nil
 }
+
+// responseKey returns the key of the field in the response, which is the alias if any.
+func (f *Field) responseKey() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Name
+}
+
+// rootFields returns the fields of a selection set after expanding fragment spreads and inline fragments.
+func rootFields(fields []*Field) []*Field {
+	var roots []*Field
+	for _, f := range fields {
+		switch {
+		case f.Fragment != nil:
+			roots = append(roots, rootFields(f.Fragment.Fields)...)
+		case f.Inline:
+			roots = append(roots, rootFields(f.Fields)...)
+		default:
+			roots = append(roots, f)
+		}
+	}
+	return roots
+}
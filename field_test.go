@@ -3,6 +3,7 @@ package graphb
 import (
 	"testing"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -13,3 +14,32 @@ func TestField_AddArguments(t *testing.T) {
 	f.AddArguments(ArgumentBool("b", true))
 	assert.Equal(t, Argument{"b", argBool(true)}, f.Arguments[0])
 }
+
+func TestMakeInlineFragment(t *testing.T) {
+	f := MakeField("search").SetFields(
+		MakeInlineFragment("User").SetFields(Fields("name")...),
+		MakeInlineFragment("Repo").SetFields(Fields("stars")...),
+		MakeInlineFragment("").SetFields(Fields("__typename")...),
+	)
+	strCh, err := f.StringChan()
+	assert.Nil(t, err)
+	assert.Equal(t, `search{...on User{name},...on Repo{stars},...{__typename}}`, StringFromChan(strCh))
+
+	_, err = MakeInlineFragment("User").StringChan()
+	assert.IsType(t, InvalidInlineFragmentErr{}, errors.Cause(err))
+	assert.Equal(t, "Inline fragment on 'User' must not have a name, alias or arguments and must have sub fields", err.Error())
+
+	_, err = MakeInlineFragment("User").SetAlias("a").SetFields(MakeField("x")).StringChan()
+	assert.IsType(t, InvalidInlineFragmentErr{}, errors.Cause(err))
+
+	_, err = MakeInlineFragment("Us er").SetFields(MakeField("x")).StringChan()
+	assert.IsType(t, InvalidNameErr{}, errors.Cause(err))
+}
+
+func TestField_nilArgumentValue(t *testing.T) {
+	_, err := MakeField("f").SetArguments(Argument{"a", nil}).StringChan()
+	assert.IsType(t, NilValueErr{}, errors.Cause(err))
+
+	_, err = MakeField("f").SetArguments(ArgumentCustomType("a", Argument{"b", nil})).StringChan()
+	assert.IsType(t, NilValueErr{}, errors.Cause(err))
+}
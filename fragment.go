@@ -0,0 +1,210 @@
+package graphb
+
+import (
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// Fragment represents a GraphQL named fragment definition, e.g. fragment UserParts on User { id, name }.
+// A Fragment is used in a selection set through its spread, see Fragment.Spread.
+// The definitions of all fragments spread by a Query are rendered after the operation.
+type Fragment struct {
+	Name       string
+	On         string // On is the type condition of the fragment.
+	Directives []Directive
+	Fields     []*Field
+	E          error
+}
+
+// Implement fieldContainer
+func (fr *Fragment) getFields() []*Field {
+	return fr.Fields
+}
+
+func (fr *Fragment) setFields(fs []*Field) {
+	fr.Fields = fs
+}
+
+// Implement directiveContainer
+func (fr *Fragment) getDirectives() []Directive {
+	return fr.Directives
+}
+
+func (fr *Fragment) setDirectives(ds []Directive) {
+	fr.Directives = ds
+}
+
+// StringChan returns read only string token channel of the fragment definition or an error.
+// Options such as OfBlockStrings configure the rendering.
+func (fr *Fragment) StringChan(options ...RenderOption) (<-chan string, error) {
+	if err := fr.check(); err != nil {
+		ch := make(chan string)
+		close(ch)
+		return ch, errors.WithStack(err)
+	}
+	o := newRenderOptions(options)
+	return tokenChan(func(sink tokenSink) {
+		fr.render(o, sink)
+	}), nil
+}
+
+// Render writes the fragment definition to w and returns the number of bytes written.
+// Nothing is written if the fragment is invalid.
+func (fr *Fragment) Render(w io.Writer, options ...RenderOption) (int64, error) {
+	if err := fr.check(); err != nil {
+		return 0, errors.WithStack(err)
+	}
+	o := newRenderOptions(options)
+	return writeTokens(w, func(sink tokenSink) {
+		fr.render(o, sink)
+	})
+}
+
+// WriteTo implements io.WriterTo. It writes the minified fragment definition like Render without options.
+func (fr *Fragment) WriteTo(w io.Writer) (int64, error) {
+	return fr.Render(w)
+}
+
+// render prints this valid Fragment with the options applied.
+func (fr *Fragment) render(o renderOptions, sink tokenSink) {
+	o.print(newTypenameInjector(o.typename).definition(fr).tokens, false, sink)
+}
+
+func (fr *Fragment) tokens(sink tokenSink) {
+	sink("fragment")
+	sink(tokenSpace)
+	sink(fr.Name)
+	sink(tokenSpace)
+	sink("on")
+	sink(tokenSpace)
+	sink(fr.On)
+	directivesTokens(sink, fr.Directives)
+	sink(tokenLB)
+	for i, field := range fr.Fields {
+		if i != 0 {
+			sink(tokenComma)
+		}
+		field.tokens(sink)
+	}
+	sink(tokenRB)
+}
+
+// check checks the validity of this Fragment, but not the fragments it spreads.
+func (fr *Fragment) check() error {
+	if !validName.MatchString(fr.Name) || fr.Name == "on" {
+		return errors.WithStack(InvalidNameErr{fragmentName, fr.Name})
+	}
+	if !validName.MatchString(fr.On) {
+		return errors.WithStack(InvalidNameErr{typeName, fr.On})
+	}
+	if len(fr.Fields) == 0 {
+		return errors.WithStack(EmptyFragmentErr{fr.Name})
+	}
+	if err := checkDirectives(fr.Directives, locationFragmentDefinition); err != nil {
+		return errors.WithStack(err)
+	}
+	for _, f := range fr.Fields {
+		if f == nil {
+			return errors.WithStack(NilFieldErr{})
+		}
+		if err := f.check(); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+////////////////
+// Public API //
+////////////////
+
+// MakeFragment constructs a Fragment of given name and type condition and returns the pointer to it.
+func MakeFragment(name string, on string) *Fragment {
+	return &Fragment{Name: name, On: on}
+}
+
+// SetFields sets the Fields field of this Fragment.
+func (fr *Fragment) SetFields(fields ...*Field) *Fragment {
+	fr.Fields = fields
+	return fr
+}
+
+// AddFields adds to the Fields field of this Fragment.
+func (fr *Fragment) AddFields(fields ...*Field) *Fragment {
+	fr.Fields = append(fr.Fields, fields...)
+	return fr
+}
+
+// SetDirectives sets the directives of the fragment definition.
+func (fr *Fragment) SetDirectives(directives ...Directive) *Fragment {
+	fr.Directives = directives
+	return fr
+}
+
+// AddDirectives adds to the directives of the fragment definition.
+func (fr *Fragment) AddDirectives(directives ...Directive) *Fragment {
+	fr.Directives = append(fr.Directives, directives...)
+	return fr
+}
+
+// Spread returns a new Field which spreads this fragment, e.g. ...UserParts.
+// The returned Field can be used wherever a *Field can be used. Directives of the spread are set on the returned Field.
+func (fr *Fragment) Spread() *Field {
+	return &Field{Fragment: fr}
+}
+
+/////////////
+// Helpers //
+/////////////
+
+// collectFragments returns all fragments spread by fields, directly or indirectly, in order of first appearance.
+// It checks every fragment it visits, fragment name uniqueness and cycles of spreads.
+func collectFragments(fields []*Field) ([]*Fragment, error) {
+	c := fragmentCollector{seen: make(map[string]*Fragment), visiting: make(map[*Fragment]bool)}
+	if err := c.collect(fields); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return c.fragments, nil
+}
+
+type fragmentCollector struct {
+	fragments []*Fragment
+	seen      map[string]*Fragment
+	visiting  map[*Fragment]bool
+}
+
+func (c *fragmentCollector) collect(fields []*Field) error {
+	for _, f := range fields {
+		if f == nil {
+			continue
+		}
+		if f.Fragment == nil {
+			if err := c.collect(f.Fields); err != nil {
+				return errors.WithStack(err)
+			}
+			continue
+		}
+		fr := f.Fragment
+		if c.visiting[fr] {
+			return errors.WithStack(CyclicFragmentErr{fr.Name})
+		}
+		if seen, ok := c.seen[fr.Name]; ok {
+			if seen != fr {
+				return errors.WithStack(DuplicateFragmentErr{fr.Name})
+			}
+			continue
+		}
+		if err := fr.check(); err != nil {
+			return errors.WithStack(err)
+		}
+		c.seen[fr.Name] = fr
+		c.fragments = append(c.fragments, fr)
+		c.visiting[fr] = true
+		if err := c.collect(fr.Fields); err != nil {
+			return errors.WithStack(err)
+		}
+		delete(c.visiting, fr)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package graphb
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFragment(t *testing.T) {
+	t.Run("method chaining", func(t *testing.T) {
+		avatar := MakeFragment("AvatarParts", "Image").SetFields(Fields("url")...)
+		user := MakeFragment("UserParts", "User").
+			SetFields(Fields("id", "name")...).
+			AddFields(MakeField("avatar").SetFields(avatar.Spread()))
+		q := MakeQuery(TypeQuery).SetFields(
+			MakeField("me").SetFields(user.Spread()),
+			MakeField("friends").SetFields(user.Spread(), MakeField("since")),
+		)
+		strCh, err := q.StringChan()
+		assert.Nil(t, err)
+		assert.Equal(t, `query{me{...UserParts},friends{...UserParts,since}}fragment UserParts on User{id,name,avatar{...AvatarParts}}fragment AvatarParts on Image{url}`, StringFromChan(strCh))
+
+		strCh, err = user.StringChan()
+		assert.Nil(t, err)
+		assert.Equal(t, `fragment UserParts on User{id,name,avatar{...AvatarParts}}`, StringFromChan(strCh))
+	})
+
+	t.Run("functional options", func(t *testing.T) {
+		user := NewFragment("UserParts", "User", OfField("id"), OfField("name"))
+		assert.Nil(t, user.E)
+		q := NewQuery(TypeQuery, OfFragmentSpread(user), OfField("me", OfFragmentSpread(user)))
+		assert.Nil(t, q.E)
+		s, err := q.JSON()
+		assert.Nil(t, err)
+		assert.Equal(t, `{"query":"query{...UserParts,me{...UserParts}}fragment UserParts on User{id,name}"}`, s)
+
+		bad := NewFragment("UserParts", "User", OfField("id", OfAlias("1")))
+		assert.IsType(t, InvalidNameErr{}, errors.Cause(bad.E))
+		q = NewQuery(TypeQuery, OfFragmentSpread(bad))
+		assert.IsType(t, InvalidNameErr{}, errors.Cause(q.E))
+
+		q = NewQuery(TypeQuery, OfFragmentSpread(nil))
+		assert.IsType(t, NilDefinitionErr{}, errors.Cause(q.E))
+	})
+
+	t.Run("struct literal", func(t *testing.T) {
+		user := &Fragment{Name: "UserParts", On: "User", Fields: Fields("id")}
+		q := Query{Type: TypeQuery, Fields: []*Field{{Name: "me", Fields: []*Field{{Fragment: user}}}}}
+		strCh, err := q.StringChan()
+		assert.Nil(t, err)
+		assert.Equal(t, `query{me{...UserParts}}fragment UserParts on User{id}`, StringFromChan(strCh))
+	})
+
+	t.Run("variables used in fragments must be defined", func(t *testing.T) {
+		user := MakeFragment("UserParts", "User").SetFields(
+			MakeField("avatar").SetArguments(ArgumentVariable("size", "size")),
+		)
+		q := MakeQuery(TypeQuery).SetFields(MakeField("me").SetFields(user.Spread()))
+		_, err := q.StringChan()
+		assert.IsType(t, UndefinedVariableErr{}, errors.Cause(err))
+
+		q.SetVariables(Variable("size", NamedType("Int")))
+		strCh, err := q.StringChan()
+		assert.Nil(t, err)
+		assert.Equal(t, `query($size:Int){me{...UserParts}}fragment UserParts on User{avatar(size:$size)}`, StringFromChan(strCh))
+	})
+}
+
+func TestFragment_check(t *testing.T) {
+	assert.IsType(t, InvalidNameErr{}, errors.Cause(MakeFragment("on", "User").check()))
+	assert.IsType(t, InvalidNameErr{}, errors.Cause(MakeFragment("1", "User").check()))
+	assert.IsType(t, InvalidNameErr{}, errors.Cause(MakeFragment("F", "").check()))
+	assert.IsType(t, NilFieldErr{}, errors.Cause(MakeFragment("F", "User").SetFields(nil).check()))
+
+	_, err := MakeQuery(TypeQuery).SetFields(MakeFragment("F", "User").Spread()).StringChan()
+	assert.Equal(t, EmptyFragmentErr{"F"}, errors.Cause(err))
+	assert.Equal(t, "Fragment 'F' must have sub fields", err.Error())
+
+	_, err = MakeFragment("F", "User").SetFields(&Field{Name: "x", Fragment: MakeFragment("G", "User")}).StringChan()
+	assert.IsType(t, InvalidFragmentSpreadErr{}, errors.Cause(err))
+	assert.Equal(t, "Fragment spread '...G' must not have a name, alias, arguments or sub fields", err.Error())
+}
+
+func Test_collectFragments(t *testing.T) {
+	t.Run("cycles", func(t *testing.T) {
+		a := MakeFragment("A", "T")
+		b := MakeFragment("B", "T").SetFields(MakeField("x").SetFields(a.Spread()))
+		a.SetFields(b.Spread())
+
+		_, err := collectFragments([]*Field{a.Spread()})
+		assert.IsType(t, CyclicFragmentErr{}, errors.Cause(err))
+		assert.Equal(t, "Fragment 'A' spreads itself", err.Error())
+
+		_, err = MakeQuery(TypeQuery).SetFields(b.Spread()).StringChan()
+		assert.IsType(t, CyclicFragmentErr{}, errors.Cause(err))
+	})
+
+	t.Run("duplicate names", func(t *testing.T) {
+		_, err := collectFragments([]*Field{
+			MakeFragment("A", "T").SetFields(MakeField("x")).Spread(),
+			MakeFragment("A", "T").SetFields(MakeField("y")).Spread(),
+		})
+		assert.IsType(t, DuplicateFragmentErr{}, errors.Cause(err))
+	})
+
+	t.Run("order of first appearance", func(t *testing.T) {
+		c := MakeFragment("C", "T").SetFields(MakeField("x"))
+		b := MakeFragment("B", "T").SetFields(c.Spread())
+		a := MakeFragment("A", "T").SetFields(c.Spread(), b.Spread())
+		fragments, err := collectFragments([]*Field{MakeField("f").SetFields(a.Spread()), b.Spread()})
+		assert.Nil(t, err)
+		assert.Equal(t, []*Fragment{a, c, b}, fragments)
+	})
+}
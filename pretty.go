@@ -0,0 +1,280 @@
+package graphb
+
+import (
+	"strings"
+	"unicode/utf16"
+)
+
+// maxLineLength is the length of a field with arguments above which the arguments are put on separate lines.
+const maxLineLength = 80
+
+// OfPretty returns a RenderOption which renders a human-readable query in the layout of graphql-js print(),
+// indented by two spaces, e.g.
+//
+//	query GetUser($id: ID!) {
+//	  user(id: $id) {
+//	    name
+//	  }
+//	}
+//
+// The pretty output consists of the same tokens as the minified output. Unlike graphql-js, the operation type of an
+// anonymous query is kept unless OfShorthand is given.
+func OfPretty() RenderOption {
+	return func(o *renderOptions) {
+		o.pretty = true
+	}
+}
+
+// OfIndent returns a RenderOption which renders like OfPretty but indents by the given string of spaces or tabs.
+func OfIndent(indent string) RenderOption {
+	return func(o *renderOptions) {
+		o.pretty = true
+		o.indent = indent
+	}
+}
+
+// OfLineBreak returns a RenderOption which renders like OfPretty but breaks lines by the given string, e.g. "\r\n".
+func OfLineBreak(lineBreak string) RenderOption {
+	return func(o *renderOptions) {
+		o.pretty = true
+		o.lineBreak = lineBreak
+	}
+}
+
+/////////////
+// Helpers //
+/////////////
+
+// pretty reformats the tokens of a valid document, operation or fragment definition, or of a single field
+// if field is true. It mirrors the printer of graphql-js.
+func pretty(tokens []string, field bool, indent string, lineBreak string) string {
+	p := prettyPrinter{tokens: tokens[:0], indent: indent}
+	for _, token := range tokens {
+		if token != "" && token != tokenSpace {
+			p.tokens = append(p.tokens, token)
+		}
+	}
+	var s string
+	if field {
+		s = p.selection()
+	} else {
+		s = p.document()
+	}
+	if lineBreak != "\n" {
+		s = strings.Replace(s, "\n", lineBreak, -1)
+	}
+	return s
+}
+
+// prettyPrinter parses minified tokens and prints every node the way graphql-js does.
+type prettyPrinter struct {
+	tokens []string
+	pos    int
+	indent string
+}
+
+func (p *prettyPrinter) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *prettyPrinter) next() string {
+	str := p.peek()
+	p.pos++
+	return str
+}
+
+// skip consumes the next token if it is str.
+func (p *prettyPrinter) skip(str string) bool {
+	if p.peek() == str {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// list parses items until the closing token, separated by commas.
+func (p *prettyPrinter) list(closing string, item func() string) []string {
+	var items []string
+	for p.pos < len(p.tokens) && !p.skip(closing) {
+		if len(items) > 0 {
+			p.skip(tokenComma)
+		}
+		items = append(items, item())
+	}
+	return items
+}
+
+func (p *prettyPrinter) document() string {
+	var definitions []string
+	for p.pos < len(p.tokens) {
+		switch p.peek() {
+		case "fragment":
+			definitions = append(definitions, p.fragment())
+		case tokenLB:
+			definitions = append(definitions, p.selectionSet())
+		default:
+			definitions = append(definitions, p.operation())
+		}
+	}
+	return join(definitions, "\n\n")
+}
+
+func (p *prettyPrinter) operation() string {
+	operation := p.next()
+	var name string
+	switch p.peek() {
+	case tokenLP, tokenAt, tokenLB:
+	default:
+		name = p.next()
+	}
+	var variables []string
+	if p.skip(tokenLP) {
+		variables = p.list(tokenRP, p.variableDefinition)
+	}
+	prefix := join([]string{operation, name + wrap("(", join(variables, ", "), ")"), join(p.directives(), " ")}, " ")
+	return prefix + " " + p.selectionSet()
+}
+
+func (p *prettyPrinter) variableDefinition() string {
+	p.skip(tokenDollar)
+	s := "$" + p.next()
+	p.skip(tokenColumn)
+	s += ": " + p.typeReference()
+	if p.skip(tokenEqual) {
+		s += " = " + p.value()
+	}
+	return s + wrap(" ", join(p.directives(), " "), "")
+}
+
+func (p *prettyPrinter) typeReference() string {
+	var s string
+	if p.skip(tokenLSB) {
+		s = "[" + p.typeReference() + "]"
+		p.skip(tokenRSB)
+	} else {
+		s = p.next()
+	}
+	if p.skip(tokenBang) {
+		s += "!"
+	}
+	return s
+}
+
+func (p *prettyPrinter) fragment() string {
+	p.next() // fragment
+	name := p.next()
+	p.skip("on")
+	on := p.next()
+	return "fragment " + name + " on " + on + " " + wrap("", join(p.directives(), " "), " ") + p.selectionSet()
+}
+
+func (p *prettyPrinter) selectionSet() string {
+	p.skip(tokenLB)
+	return block(p.list(tokenRB, p.selection), p.indent)
+}
+
+func (p *prettyPrinter) selection() string {
+	if p.skip(tokenSpread) {
+		switch p.peek() {
+		case "on", tokenAt, tokenLB:
+			var on string
+			if p.skip("on") {
+				on = "on " + p.next()
+			}
+			directives := join(p.directives(), " ")
+			return join([]string{tokenSpread, on, directives, p.selectionSet()}, " ")
+		}
+		name := p.next()
+		return tokenSpread + name + wrap(" ", join(p.directives(), " "), "")
+	}
+
+	prefix := p.next()
+	if p.skip(tokenColumn) {
+		prefix += ": " + p.next()
+	}
+	line := prefix
+	if p.skip(tokenLP) {
+		arguments := p.list(tokenRP, p.argument)
+		line = prefix + wrap("(", join(arguments, ", "), ")")
+		if length(line) > maxLineLength {
+			line = prefix + wrap("(\n", indentLines(join(arguments, "\n"), p.indent), "\n)")
+		}
+	}
+	directives := join(p.directives(), " ")
+	var selectionSet string
+	if p.peek() == tokenLB {
+		selectionSet = p.selectionSet()
+	}
+	return join([]string{line, directives, selectionSet}, " ")
+}
+
+func (p *prettyPrinter) directives() []string {
+	var directives []string
+	for p.skip(tokenAt) {
+		s := "@" + p.next()
+		if p.skip(tokenLP) {
+			s += wrap("(", join(p.list(tokenRP, p.argument), ", "), ")")
+		}
+		directives = append(directives, s)
+	}
+	return directives
+}
+
+func (p *prettyPrinter) argument() string {
+	name := p.next()
+	p.skip(tokenColumn)
+	return name + ": " + p.value()
+}
+
+func (p *prettyPrinter) value() string {
+	switch str := p.next(); str {
+	case tokenDollar:
+		return "$" + p.next()
+	case tokenLSB:
+		return "[" + join(p.list(tokenRSB, p.value), ", ") + "]"
+	case tokenLB:
+		return "{" + join(p.list(tokenRB, p.argument), ", ") + "}"
+	default:
+		return str
+	}
+}
+
+// join joins the non-empty strings by sep.
+func join(strs []string, sep string) string {
+	var nonEmpty []string
+	for _, s := range strs {
+		if s != "" {
+			nonEmpty = append(nonEmpty, s)
+		}
+	}
+	return strings.Join(nonEmpty, sep)
+}
+
+// wrap returns s between start and end, or an empty string if s is empty.
+func wrap(start, s, end string) string {
+	if s == "" {
+		return ""
+	}
+	return start + s + end
+}
+
+// indentLines indents every line of s.
+func indentLines(s string, indent string) string {
+	return wrap(indent, strings.Replace(s, "\n", "\n"+indent, -1), "")
+}
+
+// block returns the lines in braces, indented.
+func block(lines []string, indent string) string {
+	if len(lines) == 0 {
+		return tokenLB + tokenRB
+	}
+	return "{\n" + indentLines(join(lines, "\n"), indent) + "\n}"
+}
+
+// length returns the length of s in UTF-16 code units as in JavaScript.
+func length(s string) int {
+	return len(utf16.Encode([]rune(s)))
+}
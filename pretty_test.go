@@ -0,0 +1,133 @@
+package graphb
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOfPretty(t *testing.T) {
+	userParts := MakeFragment("UserParts", "User").SetDirectives(MakeDirective("dir")).SetFields(MakeField("id"))
+	q := MakeQuery(TypeQuery).
+		SetName("GetUser").
+		SetVariables(
+			Variable("id", NonNullType(NamedType("ID"))),
+			Variable("first", NamedType("Int")).SetDefault(10),
+			Variable("ids", ListType(NonNullType(NamedType("ID")))).SetDefault([]string{"a", "b"}),
+			Variable("withFriends", NamedType("Boolean")),
+		).
+		SetDirectives(MakeDirective("live")).
+		SetFields(MakeField("user").SetArguments(ArgumentVariable("id", "id")).SetFields(
+			userParts.Spread(),
+			MakeField("friends").
+				SetArguments(ArgumentVariable("first", "first"), ArgumentCustomType("orderBy", ArgumentEnum("field", "NAME"), ArgumentEnum("direction", "ASC"))).
+				SetDirectives(Include("withFriends")).
+				SetFields(
+					MakeInlineFragment("User").SetFields(MakeField("name")),
+					MakeInlineFragment("").SetDirectives(Skip("withFriends")).SetFields(MakeField("id")),
+				),
+			MakeField("avatar").SetAlias("a"),
+			MakeField("search").SetArguments(ArgumentVariable("ids", "ids"), ArgumentString("text", "a, b"), ArgumentList("empty")),
+		))
+
+	strCh, err := q.StringChan(OfPretty())
+	assert.Nil(t, err)
+	assert.Equal(t, `query GetUser($id: ID!, $first: Int = 10, $ids: [ID!] = ["a", "b"], $withFriends: Boolean) @live {
+  user(id: $id) {
+    ...UserParts
+    friends(first: $first, orderBy: {field: NAME, direction: ASC}) @include(if: $withFriends) {
+      ... on User {
+        name
+      }
+      ... @skip(if: $withFriends) {
+        id
+      }
+    }
+    a: avatar
+    search(ids: $ids, text: "a, b", empty: [])
+  }
+}
+
+fragment UserParts on User @dir {
+  id
+}`, StringFromChan(strCh))
+
+	// the pretty output consists of the same tokens
+	minified, _ := q.StringChan()
+	strCh, _ = q.StringChan(OfPretty())
+	r := strings.NewReplacer(" ", "", "\n", "", ",", "")
+	assert.Equal(t, r.Replace(StringFromChan(minified)), r.Replace(StringFromChan(strCh)))
+}
+
+func TestOfPretty_layout(t *testing.T) {
+	t.Run("long arguments", func(t *testing.T) {
+		f := MakeField("search").
+			SetArguments(ArgumentString("query", strings.Repeat("x", 50)), ArgumentInt("first", 10), ArgumentStringSlice("tags", "a")).
+			SetFields(MakeField("id"))
+		strCh, err := f.StringChan(OfPretty())
+		assert.Nil(t, err)
+		assert.Equal(t, `search(
+  query: "`+strings.Repeat("x", 50)+`"
+  first: 10
+  tags: ["a"]
+) {
+  id
+}`, StringFromChan(strCh))
+	})
+
+	t.Run("block strings", func(t *testing.T) {
+		q := MakeQuery(TypeMutation).SetFields(MakeField("post").SetFields(MakeField("create").SetArguments(ArgumentString("body", "# Title\n\nBody"))))
+		strCh, err := q.StringChan(OfBlockStrings(), OfPretty())
+		assert.Nil(t, err)
+		assert.Equal(t, "mutation {\n  post {\n    create(body: \"\"\"\n    # Title\n    \n    Body\n    \"\"\")\n  }\n}", StringFromChan(strCh))
+	})
+
+	t.Run("indent and line breaks", func(t *testing.T) {
+		q := MakeQuery(TypeQuery).SetFields(MakeField("a").SetFields(MakeField("b"), MakeField("c")))
+		strCh, err := q.StringChan(OfIndent("\t"), OfLineBreak("\r\n"))
+		assert.Nil(t, err)
+		assert.Equal(t, "query {\r\n\ta {\r\n\t\tb\r\n\t\tc\r\n\t}\r\n}", StringFromChan(strCh))
+
+		strCh, err = q.StringChan(OfPretty(), OfShorthand())
+		assert.Nil(t, err)
+		assert.Equal(t, "{\n  a {\n    b\n    c\n  }\n}", StringFromChan(strCh))
+	})
+
+	t.Run("anonymous operation with variables", func(t *testing.T) {
+		q := MakeQuery(TypeSubscription).SetVariables(Variable("id", NamedType("ID"))).SetFields(MakeField("onEvent").SetArguments(ArgumentVariable("id", "id")))
+		strCh, err := q.StringChan(OfPretty())
+		assert.Nil(t, err)
+		assert.Equal(t, "subscription ($id: ID) {\n  onEvent(id: $id)\n}", StringFromChan(strCh))
+	})
+
+	t.Run("custom values", func(t *testing.T) {
+		f := MakeField("f").SetArguments(Argument{"p", testLiteral("12.5")}, ArgumentInt("n", 1))
+		strCh, err := f.StringChan()
+		assert.Nil(t, err)
+		assert.Equal(t, "f(p:12.5,n:1)", StringFromChan(strCh))
+
+		strCh, err = f.StringChan(OfPretty())
+		assert.Nil(t, err)
+		assert.Equal(t, "f(p: 12.5, n: 1)", StringFromChan(strCh))
+	})
+
+	t.Run("document", func(t *testing.T) {
+		fr := MakeFragment("F", "T").SetFields(MakeField("x"))
+		d := MakeDocument(MakeQuery(TypeQuery).SetName("A").SetFields(fr.Spread()), MakeQuery(TypeMutation).SetName("B").SetFields(MakeField("y")))
+		strCh, err := d.StringChan(OfPretty())
+		assert.Nil(t, err)
+		assert.Equal(t, "query A {\n  ...F\n}\n\nmutation B {\n  y\n}\n\nfragment F on T {\n  x\n}", StringFromChan(strCh))
+
+		strCh, err = fr.StringChan(OfPretty())
+		assert.Nil(t, err)
+		assert.Equal(t, "fragment F on T {\n  x\n}", StringFromChan(strCh))
+	})
+}
+
+// testLiteral is a custom Value of a given literal.
+type testLiteral string
+
+func (v testLiteral) Literal() string {
+	return string(v)
+}
@@ -19,7 +19,14 @@ const (
 	tokenRB     = "}" // Right Brace
 	tokenLP     = "(" // Left Parenthesis
 	tokenRP     = ")" // Right Parenthesis
+	tokenLSB    = "[" // Left Square Bracket
+	tokenRSB    = "]" // Right Square Bracket
 	tokenColumn = ":"
 	tokenComma  = ","
 	tokenSpace  = " "
+	tokenDollar = "$"
+	tokenEqual  = "="
+	tokenBang   = "!"
+	tokenSpread = "..."
+	tokenAt     = "@"
 )
@@ -116,6 +116,115 @@ func OfName(name string) QueryOption {
 	}
 }
 
+// OfVariable returns a QueryOption which validates and adds a variable definition of given name and type to a query.
+func OfVariable(name string, Type VariableType, options ...VariableOptionInterface) QueryOption {
+	return func(query *Query) error {
+		v := Variable(name, Type)
+		for _, op := range options {
+			if err := op.runVariableOption(&v); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+		if err := v.check(); err != nil {
+			return errors.WithStack(err)
+		}
+		query.Variables = append(query.Variables, v)
+		return nil
+	}
+}
+
+// VariableOptionInterface implements functional options for OfVariable().
+type VariableOptionInterface interface {
+	runVariableOption(v *VariableDefinition) error
+}
+
+// VariableOption implements VariableOptionInterface
+type VariableOption func(v *VariableDefinition) error
+
+func (vo VariableOption) runVariableOption(v *VariableDefinition) error {
+	return vo(v)
+}
+
+// OfDefault returns a VariableOption which sets the default value of a variable.
+// The value is converted the same way as ArgumentAny does.
+func OfDefault(value interface{}) VariableOption {
+	return func(v *VariableDefinition) error {
+		if _, err := valueOf(value, "$"+v.Name); err != nil {
+			return errors.WithStack(err)
+		}
+		v.DefaultValue = value
+		return nil
+	}
+}
+
+//////////////////////
+// Fragment Factory //
+//////////////////////
+
+// NewFragment uses functional options to construct a new Fragment of given name and type condition and returns the pointer to it.
+// On error, the E field of the Fragment is set.
+func NewFragment(name string, on string, options ...FragmentOptionInterface) *Fragment {
+	fr := &Fragment{Name: name, On: on}
+	for _, op := range options {
+		if err := op.runFragmentOption(fr); err != nil {
+			fr.E = errors.WithStack(err)
+			return fr
+		}
+	}
+	return fr
+}
+
+// FragmentOptionInterface implements functional options for NewFragment().
+type FragmentOptionInterface interface {
+	runFragmentOption(fr *Fragment) error
+}
+
+//////////////////////
+// Document Factory //
+//////////////////////
+
+// NewDocument uses functional options to construct a new Document and returns the pointer to it.
+// On error, the E field of the Document is set.
+func NewDocument(options ...DocumentOption) *Document {
+	d := &Document{}
+	for _, op := range options {
+		if err := op(d); err != nil {
+			d.E = errors.WithStack(err)
+			return d
+		}
+	}
+	return d
+}
+
+// DocumentOption implements functional options for NewDocument().
+type DocumentOption func(d *Document) error
+
+// OfOperation returns a DocumentOption which adds an operation and has the same parameter signature of
+// NewQuery(Type operationType, options ...QueryOptionInterface) *Query
+func OfOperation(Type operationType, options ...QueryOptionInterface) DocumentOption {
+	return func(d *Document) error {
+		q := NewQuery(Type, options...)
+		if q.E != nil {
+			return errors.WithStack(q.E)
+		}
+		d.Operations = append(d.Operations, q)
+		return nil
+	}
+}
+
+// OfFragmentDefinitions returns a DocumentOption which adds fragment definitions to render first, see Document.Fragments.
+func OfFragmentDefinitions(fragments ...*Fragment) DocumentOption {
+	return func(d *Document) error {
+		for _, fr := range fragments {
+			if fr != nil && fr.E != nil {
+				return errors.WithStack(fr.E)
+			}
+		}
+		d.Fragments = append(d.Fragments, fragments...)
+		return nil
+	}
+}
+
 ////////////////////////////
 // fieldContainer Factory //
 ////////////////////////////
@@ -124,10 +233,10 @@ type fieldContainer interface {
 	setFields([]*Field)
 }
 
-// FieldContainerOption implements FieldOptionInterface and QueryOptionInterface,
-// which means, it can be used as the functional option for both NewQuery() and NewField().
+// FieldContainerOption implements FieldOptionInterface, QueryOptionInterface and FragmentOptionInterface,
+// which means, it can be used as the functional option for NewQuery(), NewField() and NewFragment().
 // FieldContainerOption is a function which takes in a fieldContainer and config it.
-// Both Query and Field are fieldContainer.
+// Query, Field and Fragment are all fieldContainer.
 type FieldContainerOption func(fc fieldContainer) error
 
 func (fco FieldContainerOption) runQueryOption(q *Query) error {
@@ -138,6 +247,10 @@ func (fco FieldContainerOption) runFieldOption(f *Field) error {
 	return fco(f)
 }
 
+func (fco FieldContainerOption) runFragmentOption(fr *Fragment) error {
+	return fco(fr)
+}
+
 // OfField returns a FieldContainerOption and has the same parameter signature of
 // NewField(name string, options ...FieldOptionInterface) (*Field, error)
 func OfField(name string, options ...FieldOptionInterface) FieldContainerOption {
@@ -151,6 +264,85 @@ func OfField(name string, options ...FieldOptionInterface) FieldContainerOption
 	}
 }
 
+// OfFragmentSpread returns a FieldContainerOption which adds a spread of the given fragment to the fields.
+// Options such as OfDirective configure the spread.
+func OfFragmentSpread(fragment *Fragment, options ...FieldOptionInterface) FieldContainerOption {
+	return func(fc fieldContainer) error {
+		if fragment == nil {
+			return errors.WithStack(NilDefinitionErr{})
+		}
+		if fragment.E != nil {
+			return errors.WithStack(fragment.E)
+		}
+		f := fragment.Spread()
+		for _, op := range options {
+			if err := op.runFieldOption(f); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+		fc.setFields(append(fc.getFields(), f))
+		return nil
+	}
+}
+
+// OfInlineFragment returns a FieldContainerOption which adds an inline fragment of given type condition to the fields.
+// The type condition is optional and can be empty. Options such as OfField set the selection set of the inline fragment.
+func OfInlineFragment(on string, options ...FieldOptionInterface) FieldContainerOption {
+	return func(fc fieldContainer) error {
+		f := MakeInlineFragment(on)
+		for _, op := range options {
+			if err := op.runFieldOption(f); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+		fc.setFields(append(fc.getFields(), f))
+		return nil
+	}
+}
+
+////////////////////////////////
+// directiveContainer Factory //
+////////////////////////////////
+
+type directiveContainer interface {
+	getDirectives() []Directive
+	setDirectives([]Directive)
+}
+
+// DirectiveContainerOption implements FieldOptionInterface, QueryOptionInterface, FragmentOptionInterface and VariableOptionInterface,
+// which means, it can be used as the functional option for NewField(), NewQuery(), NewFragment() and OfVariable().
+// Field, Query, Fragment and VariableDefinition are all directiveContainer.
+type DirectiveContainerOption func(dc directiveContainer) error
+
+func (dco DirectiveContainerOption) runFieldOption(f *Field) error {
+	return dco(f)
+}
+
+func (dco DirectiveContainerOption) runQueryOption(q *Query) error {
+	return dco(q)
+}
+
+func (dco DirectiveContainerOption) runFragmentOption(fr *Fragment) error {
+	return dco(fr)
+}
+
+func (dco DirectiveContainerOption) runVariableOption(v *VariableDefinition) error {
+	return dco(v)
+}
+
+// OfDirective returns a DirectiveContainerOption which validates and adds a directive of given name and arguments.
+// Whether the directive is allowed on its location is checked when the Query is rendered.
+func OfDirective(name string, arguments ...Argument) DirectiveContainerOption {
+	return func(dc directiveContainer) error {
+		d := MakeDirective(name, arguments...)
+		if err := d.check(); err != nil {
+			return errors.WithStack(err)
+		}
+		dc.setDirectives(append(dc.getDirectives(), d))
+		return nil
+	}
+}
+
 // Fields takes a list of strings and make them a slice of *Field.
 // This is useful when you want fields with no sub fields.
 // For example:
@@ -1,7 +1,7 @@
 package graphb
 
 import (
-	"fmt"
+	"io"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -11,10 +11,12 @@ import (
 // Though all fields (Go struct field, not GraphQL field) of this struct is public,
 // the author recommends you to use functions in public.go.
 type Query struct {
-	Type   operationType // The operation type is either query, mutation, or subscription.
-	Name   string        // The operation name is a meaningful and explicit name for your operation.
-	Fields []*Field
-	E      error
+	Type       operationType // The operation type is either query, mutation, or subscription.
+	Name       string        // The operation name is a meaningful and explicit name for your operation.
+	Variables  []VariableDefinition
+	Directives []Directive
+	Fields     []*Field
+	E          error
 }
 
 // implements fieldContainer
@@ -26,56 +28,89 @@ func (q *Query) setFields(fs []*Field) {
 	q.Fields = fs
 }
 
+// implements directiveContainer
+func (q *Query) getDirectives() []Directive {
+	return q.Directives
+}
+
+func (q *Query) setDirectives(ds []Directive) {
+	q.Directives = ds
+}
+
 // StringChan returns a string channel and an error.
 // When error is not nil, the channel is nil.
 // When error is nil, the channel is guaranteed to be closed.
 // Warning: One should never receive from a nil channel for eternity awaits by a nil channel.
-func (q *Query) StringChan() (<-chan string, error) {
+// Options such as OfBlockStrings configure the rendering.
+func (q *Query) StringChan(options ...RenderOption) (<-chan string, error) {
 	ch := make(chan string)
 
 	if err := q.check(); err != nil {
 		close(ch)
 		return ch, errors.WithStack(err)
 	}
+	o := newRenderOptions(options)
+	return tokenChan(func(sink tokenSink) {
+		q.render(o, sink)
+	}), nil
+}
 
-	for _, f := range q.Fields {
-		if f == nil {
-			close(ch)
-			return ch, errors.WithStack(NilFieldErr{})
-		}
-		if err := f.check(); err != nil {
-			close(ch)
-			return ch, errors.WithStack(err)
+// render prints this valid Query with the options applied.
+func (q *Query) render(o renderOptions, sink tokenSink) {
+	q = newTypenameInjector(o.typename).query(q)
+	node := q.tokens
+	if o.shorthand && q.isShorthand() {
+		node = func(sink tokenSink) {
+			q.tokens(skipFirst(sink)) // the operation type
 		}
 	}
-	return q.stringChan(), nil
+	o.print(node, false, sink)
 }
 
-// StringChan returns a read only channel which is guaranteed to be closed in the future.
-func (q *Query) stringChan() <-chan string {
-	tokenChan := make(chan string)
-	go func() {
-		tokenChan <- strings.ToLower(string(q.Type))
-		// emit operation name
-		if q.Name != "" {
-			tokenChan <- tokenSpace
-			tokenChan <- q.Name
-		}
-		// emit fields
-		tokenChan <- tokenLB
-		for i, field := range q.Fields {
+// tokens prints the operation definition followed by the definitions of the fragments it spreads.
+func (q *Query) tokens(sink tokenSink) {
+	q.operationTokens(sink)
+	fragments, _ := collectFragments(q.Fields)
+	for _, fr := range fragments {
+		fr.tokens(sink)
+	}
+}
+
+// operationTokens prints the operation definition without the fragments it spreads.
+func (q *Query) operationTokens(sink tokenSink) {
+	sink(strings.ToLower(string(q.Type)))
+	// emit operation name
+	if q.Name != "" {
+		sink(tokenSpace)
+		sink(q.Name)
+	}
+	// emit variable definitions
+	if len(q.Variables) > 0 {
+		sink(tokenLP)
+		for i, v := range q.Variables {
 			if i != 0 {
-				tokenChan <- tokenComma
-			}
-			strs := field.stringChan()
-			for str := range strs {
-				tokenChan <- str
+				sink(tokenComma)
 			}
+			v.tokens(sink)
+		}
+		sink(tokenRP)
+	}
+	// emit directives
+	directivesTokens(sink, q.Directives)
+	// emit fields
+	sink(tokenLB)
+	for i, field := range q.Fields {
+		if i != 0 {
+			sink(tokenComma)
 		}
-		tokenChan <- tokenRB
-		close(tokenChan)
-	}()
-	return tokenChan
+		field.tokens(sink)
+	}
+	sink(tokenRB)
+}
+
+// isShorthand reports whether the query can be written in the shorthand form, that is, without the operation type.
+func (q *Query) isShorthand() bool {
+	return strings.ToLower(string(q.Type)) == string(TypeQuery) && q.Name == "" && len(q.Variables) == 0 && len(q.Directives) == 0
 }
 
 func (q *Query) check() error {
@@ -86,6 +121,58 @@ func (q *Query) check() error {
 	if err := q.checkName(); err != nil {
 		return errors.WithStack(err)
 	}
+	if err := q.checkVariableDefinitions(); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := checkDirectives(q.Directives, operationLocation(q.Type)); err != nil {
+		return errors.WithStack(err)
+	}
+
+	// check fields
+	for _, f := range q.Fields {
+		if f == nil {
+			return errors.WithStack(NilFieldErr{})
+		}
+		if err := f.check(); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	// check spread fragments
+	fragments, err := collectFragments(q.Fields)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if strings.ToLower(string(q.Type)) == string(TypeSubscription) {
+		if err := q.checkSubscription(); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	// check that all referenced variables are defined
+	defined := make(map[string]bool, len(q.Variables))
+	for _, v := range q.Variables {
+		defined[v.Name] = true
+	}
+	names := directivesVariables(q.Directives)
+	for _, fr := range fragments {
+		names = append(names, directivesVariables(fr.Directives)...)
+	}
+	for _, name := range names {
+		if !defined[name] {
+			return errors.WithStack(UndefinedVariableErr{name})
+		}
+	}
+	fields := append([]*Field{}, q.Fields...)
+	for _, fr := range fragments {
+		fields = append(fields, fr.Fields...)
+	}
+	for _, f := range fields {
+		if err := f.checkVariables(defined); err != nil {
+			return errors.WithStack(err)
+		}
+	}
 	return nil
 }
 
@@ -96,6 +183,42 @@ func (q *Query) checkName() error {
 	return nil
 }
 
+// checkSubscription checks that the subscription selects a single root field, which is not an introspection field,
+// after expanding fragment spreads and inline fragments. Fragments must have been checked for cycles.
+// See: https://spec.graphql.org/October2021/#sec-Single-root-field
+func (q *Query) checkSubscription() error {
+	var keys []string
+	byKey := make(map[string]*Field)
+	for _, f := range rootFields(q.Fields) {
+		key := f.responseKey()
+		if byKey[key] == nil {
+			byKey[key] = f
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) != 1 {
+		return errors.WithStack(InvalidSubscriptionErr{q.Name, keys})
+	}
+	if name := byKey[keys[0]].Name; strings.HasPrefix(name, "__") {
+		return errors.WithStack(InvalidSubscriptionErr{q.Name, []string{name}})
+	}
+	return nil
+}
+
+func (q *Query) checkVariableDefinitions() error {
+	names := make(map[string]bool, len(q.Variables))
+	for _, v := range q.Variables {
+		if err := v.check(); err != nil {
+			return errors.WithStack(err)
+		}
+		if names[v.Name] {
+			return errors.WithStack(DuplicateVariableErr{v.Name})
+		}
+		names[v.Name] = true
+	}
+	return nil
+}
+
 ////////////////
 // Public API //
 ////////////////
@@ -106,13 +229,54 @@ func MakeQuery(Type operationType) *Query {
 }
 
 // JSON returns a json string with "query" field.
-func (q *Query) JSON() (string, error) {
-	strCh, err := q.StringChan()
-	if err != nil {
+// Options such as OfBlockStrings configure the rendering of the query.
+func (q *Query) JSON(options ...RenderOption) (string, error) {
+	var b strings.Builder
+	if _, err := q.WriteJSON(&b, options...); err != nil {
 		return "", errors.WithStack(err)
 	}
-	s := StringFromChan(strCh)
-	return fmt.Sprintf(`{"query":"%s"}`, strings.Replace(s, `"`, `\"`, -1)), nil
+	return b.String(), nil
+}
+
+// WriteJSON writes the json request body with "query" field to w and returns the number of bytes written.
+// Nothing is written if the query is invalid.
+func (q *Query) WriteJSON(w io.Writer, options ...RenderOption) (int64, error) {
+	if err := q.check(); err != nil {
+		return 0, errors.WithStack(err)
+	}
+	o := newRenderOptions(options)
+	return writeJSON(w, func(sink tokenSink) {
+		q.render(o, sink)
+	}, "")
+}
+
+// JSONReader returns a reader of the json request body with "query" field, e.g. for http.NewRequest,
+// which sets the content length from it. The query is rendered when JSONReader is called.
+func (q *Query) JSONReader(options ...RenderOption) (io.Reader, error) {
+	if err := q.check(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	o := newRenderOptions(options)
+	r, err := jsonReader(func(sink tokenSink) {
+		q.render(o, sink)
+	}, "")
+	return r, errors.WithStack(err)
+}
+
+// Render writes the query to w and returns the number of bytes written. Nothing is written if the query is invalid.
+func (q *Query) Render(w io.Writer, options ...RenderOption) (int64, error) {
+	if err := q.check(); err != nil {
+		return 0, errors.WithStack(err)
+	}
+	o := newRenderOptions(options)
+	return writeTokens(w, func(sink tokenSink) {
+		q.render(o, sink)
+	})
+}
+
+// WriteTo implements io.WriterTo. It writes the minified query like Render without options.
+func (q *Query) WriteTo(w io.Writer) (int64, error) {
+	return q.Render(w)
 }
 
 // SetName sets the Name field of this Query.
@@ -121,6 +285,32 @@ func (q *Query) SetName(name string) *Query {
 	return q
 }
 
+// SetVariables sets the variable definitions of this Query.
+// If q.Variables already contains data, they will be replaced.
+func (q *Query) SetVariables(variables ...VariableDefinition) *Query {
+	q.Variables = variables
+	return q
+}
+
+// AddVariables adds to the variable definitions of this Query.
+func (q *Query) AddVariables(variables ...VariableDefinition) *Query {
+	q.Variables = append(q.Variables, variables...)
+	return q
+}
+
+// SetDirectives sets the directives of this Query.
+// If q.Directives already contains data, they will be replaced.
+func (q *Query) SetDirectives(directives ...Directive) *Query {
+	q.Directives = directives
+	return q
+}
+
+// AddDirectives adds to the directives of this Query.
+func (q *Query) AddDirectives(directives ...Directive) *Query {
+	q.Directives = append(q.Directives, directives...)
+	return q
+}
+
 // GetField return the field identified by the name. Nil if not exist.
 func (q *Query) GetField(name string) *Field {
 	for _, f := range q.Fields {
@@ -1,6 +1,7 @@
 package graphb
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
 
@@ -53,7 +54,7 @@ func TestQuery_JSON(t *testing.T) {
 					),
 			)
 
-		c := q.stringChan()
+		c := tokenChan(q.tokens)
 
 		var strs []string
 		for str := range c {
@@ -64,3 +65,84 @@ func TestQuery_JSON(t *testing.T) {
 	})
 
 }
+
+func TestQuery_checkVariableUsage(t *testing.T) {
+	q := NewQuery(
+		TypeQuery,
+		OfVariable("id", NonNullType(NamedType("ID"))),
+		OfField(
+			"user",
+			OfArguments(ArgumentVariable("id", "id")),
+			OfField("friends", OfArguments(ArgumentCustomType("filter", ArgumentVariable("after", "after")))),
+		),
+	)
+	assert.Nil(t, q.E)
+	_, err := q.StringChan()
+	assert.IsType(t, UndefinedVariableErr{}, errors.Cause(err))
+	assert.Equal(t, "Variable '$after' is not defined by the operation", err.Error())
+
+	q.AddVariables(Variable("after", NamedType("String")))
+	s, err := q.JSON()
+	assert.Nil(t, err)
+	assert.Equal(t, `{"query":"query($id:ID!,$after:String){user(id:$id){friends(filter:{after:$after})}}"}`, s)
+}
+
+func FuzzQuery_JSON(f *testing.F) {
+	for _, s := range []string{"", `"`, `\`, `\"`, "\n", "\x00", "\u2028", "😀", "\xff", `"}`, "</script>"} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		q := MakeQuery(TypeMutation).SetFields(
+			MakeField("update").SetArguments(ArgumentString("s", s), ArgumentStringSlice("l", s, s)),
+		)
+		str, err := q.JSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var body struct {
+			Query string `json:"query"`
+		}
+		if err := json.Unmarshal([]byte(str), &body); err != nil {
+			t.Fatalf("JSON %s of %q is invalid: %v", str, s, err)
+		}
+		strCh, err := q.StringChan()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if query := StringFromChan(strCh); body.Query != query {
+			t.Fatalf("JSON %s does not contain query %s", str, query)
+		}
+	})
+}
+
+func TestQuery_checkSubscription(t *testing.T) {
+	fr := MakeFragment("Events", "Subscription").SetFields(MakeField("a"), MakeField("b"))
+	one := MakeFragment("One", "Subscription").SetFields(MakeField("a").SetFields(MakeField("id")))
+
+	cases := []struct {
+		name string
+		q    *Query
+		err  error
+	}{
+		{"single field", MakeQuery(TypeSubscription).SetFields(MakeField("a").SetFields(MakeField("id"))), nil},
+		{"same response key twice", MakeQuery(TypeSubscription).SetFields(MakeField("a"), one.Spread(), MakeInlineFragment("").SetFields(MakeField("a"))), nil},
+		{"two fields", MakeQuery(TypeSubscription).SetName("S").SetFields(MakeField("a"), MakeField("b")), InvalidSubscriptionErr{"S", []string{"a", "b"}}},
+		{"aliased", MakeQuery(TypeSubscription).SetFields(MakeField("a"), MakeField("a").SetAlias("a2")), InvalidSubscriptionErr{"", []string{"a", "a2"}}},
+		{"fragment", MakeQuery(TypeSubscription).SetFields(fr.Spread()), InvalidSubscriptionErr{"", []string{"a", "b"}}},
+		{"inline fragment", MakeQuery(TypeSubscription).SetFields(MakeInlineFragment("Subscription").SetFields(MakeField("a")), MakeField("c")), InvalidSubscriptionErr{"", []string{"a", "c"}}},
+		{"no field", MakeQuery(TypeSubscription), InvalidSubscriptionErr{}},
+		{"introspection", MakeQuery(TypeSubscription).SetFields(MakeField("__typename").SetAlias("t")), InvalidSubscriptionErr{"", []string{"__typename"}}},
+		{"query", MakeQuery(TypeQuery).SetFields(MakeField("a"), MakeField("__typename")), nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := c.q.StringChan()
+			assert.Equal(t, c.err, errors.Cause(err))
+		})
+	}
+
+	_, err := MakeQuery(TypeSubscription).SetName("S").SetFields(MakeField("a"), MakeField("b")).StringChan()
+	assert.Equal(t, "Subscription 'S' must select only one top level field, but selects 2: a, b", err.Error())
+	_, err = MakeQuery(TypeSubscription).SetFields(MakeField("__typename")).StringChan()
+	assert.Equal(t, "Anonymous Subscription must not select an introspection top level field '__typename'", err.Error())
+}
@@ -0,0 +1,509 @@
+package graphb
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	valueType         = reflect.TypeOf((*Value)(nil)).Elem()
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	jsonNumberType    = reflect.TypeOf(json.Number(""))
+)
+
+// valueOf converts a Go value to its Value representation, see ArgumentAny.
+// root names the value in the path reported by errors.
+func valueOf(value interface{}, root string) (Value, error) {
+	c := valueConverter{root: root, seen: make(map[seenKey]bool)}
+	return c.convert(reflect.ValueOf(value), "")
+}
+
+// valueConverter converts Go values by reflection.
+type valueConverter struct {
+	root string
+	seen map[seenKey]bool // pointers, maps and slices on the current path, to detect cycles
+}
+
+// seenKey identifies a pointer or map by its address, and a slice by the address of its data and its length,
+// like encoding/json does.
+type seenKey struct {
+	ptr uintptr
+	len int
+}
+
+// convert converts rv, which is found at path relative to the root value.
+func (c *valueConverter) convert(rv reflect.Value, path string) (Value, error) {
+	if !rv.IsValid() {
+		return argNull{}, nil
+	}
+	if (rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface) && rv.IsNil() {
+		return argNull{}, nil
+	}
+	if v, ok := implementation(rv, valueType); ok {
+		return v.(Value), nil
+	}
+	if rv.Type() == jsonNumberType {
+		return c.number(rv.String(), path)
+	}
+	if m, ok := implementation(rv, jsonMarshalerType); ok {
+		data, err := m.(json.Marshaler).MarshalJSON()
+		if err != nil {
+			return nil, MarshalValueErr{c.fullPath(path), err}
+		}
+		v, err := jsonValue(data)
+		if err != nil {
+			return nil, MarshalValueErr{c.fullPath(path), err}
+		}
+		return v, nil
+	}
+	if m, ok := implementation(rv, textMarshalerType); ok {
+		text, err := m.(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return nil, MarshalValueErr{c.fullPath(path), err}
+		}
+		return argString(text), nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if rv.Kind() == reflect.Ptr {
+			if err := c.enter(rv, path); err != nil {
+				return nil, err
+			}
+			defer c.leave(rv)
+		}
+		return c.convert(rv.Elem(), path)
+
+	case reflect.Bool:
+		return argBool(rv.Bool()), nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i := rv.Int()
+		if int64(int(i)) != i {
+			return argNumber(strconv.FormatInt(i, 10)), nil
+		}
+		return argInt(int(i)), nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u := rv.Uint()
+		if u > uint64(^uint(0)>>1) {
+			return argNumber(strconv.FormatUint(u, 10)), nil
+		}
+		return argInt(int(u)), nil
+
+	case reflect.Float32:
+		return argFloat(float32To64(float32(rv.Float()))), nil
+	case reflect.Float64:
+		return argFloat(rv.Float()), nil
+
+	case reflect.String:
+		return argString(rv.String()), nil
+
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.Len() > 0 {
+			if err := c.enter(rv, path); err != nil {
+				return nil, err
+			}
+			defer c.leave(rv)
+		}
+		return c.list(rv, path)
+
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			break
+		}
+		if rv.IsNil() {
+			return argNull{}, nil
+		}
+		if err := c.enter(rv, path); err != nil {
+			return nil, err
+		}
+		defer c.leave(rv)
+		return c.object(rv, path)
+
+	case reflect.Struct:
+		return c.structObject(rv, path)
+	}
+	return nil, c.notSupported(rv, path)
+}
+
+// number converts a json.Number to a number literal, like encoding/json does.
+func (c *valueConverter) number(n string, path string) (Value, error) {
+	if n == "" {
+		n = "0"
+	}
+	if v, err := jsonValue([]byte(n)); err != nil || v != argNumber(n) {
+		return nil, MarshalValueErr{c.fullPath(path), errors.Errorf("invalid number literal %q", n)}
+	}
+	return argNumber(n), nil
+}
+
+// list converts a slice or an array. Slices of plain booleans, ints, floats and strings keep their dedicated types.
+func (c *valueConverter) list(rv reflect.Value, path string) (Value, error) {
+	n := rv.Len()
+	if elemType := rv.Type().Elem(); plainType(elemType) {
+		switch elemType.Kind() {
+		case reflect.Bool:
+			s := make(argBoolSlice, n)
+			for i := range s {
+				s[i] = rv.Index(i).Bool()
+			}
+			return s, nil
+		case reflect.Int:
+			s := make(argIntSlice, n)
+			for i := range s {
+				s[i] = int(rv.Index(i).Int())
+			}
+			return s, nil
+		case reflect.Float32, reflect.Float64:
+			s := make(argFloatSlice, n)
+			for i := range s {
+				s[i] = rv.Index(i).Float()
+				if elemType.Kind() == reflect.Float32 {
+					s[i] = float32To64(float32(s[i]))
+				}
+			}
+			return s, nil
+		case reflect.String:
+			s := make(argStringSlice, n)
+			for i := range s {
+				s[i] = rv.Index(i).String()
+			}
+			return s, nil
+		}
+	}
+
+	list := make(argList, n)
+	for i := range list {
+		elem, err := c.convert(rv.Index(i), path+"["+strconv.Itoa(i)+"]")
+		if err != nil {
+			return nil, err
+		}
+		list[i] = elem
+	}
+	return list, nil
+}
+
+// object converts a map of string keys to a custom type whose fields are ordered by key.
+func (c *valueConverter) object(rv reflect.Value, path string) (Value, error) {
+	keys := rv.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+	obj := make(argumentSlice, len(keys))
+	for i, key := range keys {
+		v, err := c.convert(rv.MapIndex(key), path+"."+key.String())
+		if err != nil {
+			return nil, err
+		}
+		obj[i] = Argument{key.String(), v}
+	}
+	return obj, nil
+}
+
+// structObject converts a struct to a custom type whose fields are the struct fields, see structFields.
+func (c *valueConverter) structObject(rv reflect.Value, path string) (Value, error) {
+	fields := structFields(rv.Type())
+	obj := make(argumentSlice, 0, len(fields))
+	for _, field := range fields {
+		fv, ok := fieldByIndex(rv, field.index)
+		if !ok || field.omitEmpty && isEmptyValue(fv) {
+			continue
+		}
+		v, err := c.convert(fv, path+"."+field.name)
+		if err != nil {
+			return nil, err
+		}
+		if field.enum {
+			v = enumValue(v)
+		}
+		obj = append(obj, Argument{field.name, v})
+	}
+	return obj, nil
+}
+
+func (c *valueConverter) enter(rv reflect.Value, path string) error {
+	key := seenOf(rv)
+	if c.seen[key] {
+		return CyclicValueErr{c.fullPath(path)}
+	}
+	c.seen[key] = true
+	return nil
+}
+
+func (c *valueConverter) leave(rv reflect.Value) {
+	delete(c.seen, seenOf(rv))
+}
+
+func seenOf(rv reflect.Value) seenKey {
+	key := seenKey{ptr: rv.Pointer()}
+	if rv.Kind() == reflect.Slice {
+		key.len = rv.Len()
+	}
+	return key
+}
+
+func (c *valueConverter) notSupported(rv reflect.Value, path string) error {
+	err := ArgumentTypeNotSupportedErr{}
+	if rv.CanInterface() {
+		err.Value = rv.Interface()
+	}
+	if path != "" {
+		err.Path = c.fullPath(path)
+	}
+	return err
+}
+
+func (c *valueConverter) fullPath(path string) string {
+	return c.root + path
+}
+
+// implementation returns rv as an implementation of the interface type it, if the type of rv or its pointer type implements it.
+func implementation(rv reflect.Value, it reflect.Type) (interface{}, bool) {
+	if !rv.CanInterface() {
+		return nil, false
+	}
+	if rv.Type().Implements(it) {
+		return rv.Interface(), true
+	}
+	if rv.CanAddr() && reflect.PtrTo(rv.Type()).Implements(it) {
+		return rv.Addr().Interface(), true
+	}
+	return nil, false
+}
+
+// plainType reports whether values of type t are converted by their kind only,
+// that is, t is not json.Number and neither t nor its pointer type implements Value, json.Marshaler or encoding.TextMarshaler.
+func plainType(t reflect.Type) bool {
+	if t == jsonNumberType {
+		return false
+	}
+	for _, it := range []reflect.Type{valueType, jsonMarshalerType, textMarshalerType} {
+		if t.Implements(it) || reflect.PtrTo(t).Implements(it) {
+			return false
+		}
+	}
+	return true
+}
+
+// jsonValue converts a JSON value to the matching GraphQL value. The fields of objects keep their order.
+func jsonValue(data []byte) (Value, error) {
+	if !json.Valid(data) {
+		return nil, errors.Errorf("invalid JSON %q", data)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return decodeJSONValue(dec)
+}
+
+func decodeJSONValue(dec *json.Decoder) (Value, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	switch token := token.(type) {
+	case bool:
+		return argBool(token), nil
+	case json.Number:
+		return argNumber(token), nil
+	case string:
+		return argString(token), nil
+	case json.Delim:
+		var v Value
+		if token == '[' {
+			list := argList{}
+			for dec.More() {
+				elem, err := decodeJSONValue(dec)
+				if err != nil {
+					return nil, errors.WithStack(err)
+				}
+				list = append(list, elem)
+			}
+			v = list
+		} else {
+			obj := argumentSlice{}
+			for dec.More() {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, errors.WithStack(err)
+				}
+				field, err := decodeJSONValue(dec)
+				if err != nil {
+					return nil, errors.WithStack(err)
+				}
+				obj = append(obj, Argument{key.(string), field})
+			}
+			v = obj
+		}
+		if _, err := dec.Token(); err != nil { // the closing delimiter
+			return nil, errors.WithStack(err)
+		}
+		return v, nil
+	}
+	return argNull{}, nil
+}
+
+// structField is a struct field which becomes a field of a custom type.
+type structField struct {
+	name      string
+	index     []int // index is the index sequence for reflect.Value.FieldByIndex.
+	tagged    bool  // tagged reports whether the name comes from the graphql tag.
+	omitEmpty bool
+	enum      bool
+}
+
+// structFields returns the fields of struct type t in declaration order. Like encoding/json, it
+//   - skips unexported fields and fields tagged graphql:"-"
+//   - names a field by its graphql tag, e.g. graphql:"name,omitempty,enum", or else by its Go name
+//   - flattens embedded structs without a tag name into t; of the fields with the same name, the least nested one
+//     wins, then a tagged one, and fields with the same name which do not have a winner are skipped
+func structFields(t reflect.Type) []structField {
+	var fields []structField
+	visiting := make(map[reflect.Type]bool)
+	var walk func(t reflect.Type, index []int)
+	walk = func(t reflect.Type, index []int) {
+		visiting[t] = true
+		defer delete(visiting, t)
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag := f.Tag.Get("graphql")
+			if tag == "-" {
+				continue
+			}
+			name, opts := parseTag(tag)
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			fieldIndex := append(append([]int(nil), index...), i)
+			if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+				if !visiting[ft] {
+					walk(ft, fieldIndex)
+				}
+				continue
+			}
+			if f.PkgPath != "" {
+				continue // unexported
+			}
+			field := structField{name: name, index: fieldIndex, tagged: name != ""}
+			if field.name == "" {
+				field.name = f.Name
+			}
+			for _, opt := range opts {
+				switch opt {
+				case "omitempty":
+					field.omitEmpty = true
+				case "enum":
+					field.enum = true
+				}
+			}
+			fields = append(fields, field)
+		}
+	}
+	walk(t, nil)
+
+	// keep the dominant field of every name
+	byName := make(map[string][]int)
+	for i, f := range fields {
+		byName[f.name] = append(byName[f.name], i)
+	}
+	var result []structField
+	for i, f := range fields {
+		if dominantField(fields, byName[f.name]) == i {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+// dominantField returns the position of the field which wins among the fields at positions of the same name,
+// or -1 if there is none.
+func dominantField(fields []structField, positions []int) int {
+	depth := len(fields[positions[0]].index)
+	for _, i := range positions {
+		if len(fields[i].index) < depth {
+			depth = len(fields[i].index)
+		}
+	}
+	winner, count, tagged, taggedCount := -1, 0, -1, 0
+	for _, i := range positions {
+		if len(fields[i].index) != depth {
+			continue
+		}
+		winner, count = i, count+1
+		if fields[i].tagged {
+			tagged, taggedCount = i, taggedCount+1
+		}
+	}
+	switch {
+	case count == 1:
+		return winner
+	case taggedCount == 1:
+		return tagged
+	}
+	return -1
+}
+
+// parseTag splits a graphql struct tag into the name and the options.
+func parseTag(tag string) (string, []string) {
+	parts := strings.Split(tag, ",")
+	return parts[0], parts[1:]
+}
+
+// fieldByIndex is like reflect.Value.FieldByIndex, but reports false for a field of a nil embedded pointer.
+func fieldByIndex(rv reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				return reflect.Value{}, false
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(x)
+	}
+	return rv, true
+}
+
+// isEmptyValue reports whether rv is empty the same way as encoding/json does for omitempty.
+func isEmptyValue(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return rv.Len() == 0
+	case reflect.Bool:
+		return !rv.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return rv.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return rv.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// enumValue converts the strings of v, at any depth of lists, to enum values.
+func enumValue(v Value) Value {
+	switch v := v.(type) {
+	case argString:
+		return argEnum(v)
+	case argStringSlice:
+		return argEnumSlice(v)
+	case argList:
+		list := make(argList, len(v))
+		for i, elem := range v {
+			list[i] = enumValue(elem)
+		}
+		return list
+	}
+	return v
+}
@@ -0,0 +1,205 @@
+package graphb
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type testOrder string
+
+type testItem struct {
+	Name     string
+	Price    interface{}
+	quantity int
+}
+
+type testNode struct {
+	Name string
+	Next *testNode
+}
+
+func TestArgumentAny_reflect(t *testing.T) {
+	str := "s"
+	cases := []struct {
+		name   string
+		value  interface{}
+		output string
+	}{
+		{"int64", int64(-1 << 62), "-4611686018427387904"},
+		{"uint8", uint8(255), "255"},
+		{"uint64", uint64(1<<64 - 1), "18446744073709551615"},
+		{"uintptr", uintptr(3), "3"},
+		{"float64 slice", []float64{1, 2.5}, "[1.0,2.5]"},
+		{"int32 slice", []int32{1, 2}, "[1,2]"},
+		{"array", [2]string{"a", "b"}, `["a","b"]`},
+		{"pointer", &str, `"s"`},
+		{"nil pointer", (*string)(nil), "null"},
+		{"named string", testOrder("ASC"), `"ASC"`},
+		{"named string slice", []testOrder{"ASC"}, `["ASC"]`},
+		{"enum slice", []Value{ValueEnum("ASC")}, `[ASC]`},
+		{"map", map[string]interface{}{"b": 1, "a": []int{2}, "c": nil}, `{a:[2],b:1,c:null}`},
+		{"nil map", map[string]int(nil), "null"},
+		{"struct", testItem{Name: "x", Price: 1.5, quantity: 1}, `{Name:"x",Price:1.5}`},
+		{"nested", []interface{}{map[string]testItem{"k": {Name: "y"}}}, `[{k:{Name:"y",Price:null}}]`},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			arg, err := ArgumentAny("arg", c.value)
+			assert.Nil(t, err)
+			assert.Nil(t, checkValue(arg.Value))
+			assert.Equal(t, c.output, arg.Value.Literal())
+		})
+	}
+}
+
+func TestArgumentAny_reflectErrors(t *testing.T) {
+	_, err := ArgumentAny("input", map[string]interface{}{
+		"items": []testItem{{Price: 1}, {Price: 2}, {Price: 3i}},
+	})
+	assert.Equal(t, ArgumentTypeNotSupportedErr{Value: 3i, Path: "input.items[2].Price"}, err)
+	assert.Equal(t, "Argument (0+3i) of Type complex128 at input.items[2].Price is not supported", err.Error())
+
+	_, err = ArgumentAny("input", map[int]string{1: "a"})
+	assert.Equal(t, "Argument map[1:a] of Type map[int]string is not supported", err.Error())
+
+	n := &testNode{Name: "a"}
+	n.Next = &testNode{Name: "b", Next: n}
+	_, err = ArgumentAny("node", n)
+	assert.Equal(t, CyclicValueErr{"node.Next.Next"}, err)
+
+	x := []interface{}{nil}
+	x[0] = x
+	_, err = ArgumentAny("x", x)
+	assert.Equal(t, CyclicValueErr{"x[0]"}, err)
+
+	// the same slice twice on different paths is no cycle
+	shared := []interface{}{1}
+	arg, err := ArgumentAny("y", []interface{}{shared, shared})
+	assert.Nil(t, err)
+	assert.Equal(t, "y:[[1],[1]]", tokenString(arg.tokens))
+}
+
+type testTimestamps struct {
+	CreatedAt string `graphql:"createdAt,omitempty"`
+	ID        string `graphql:"id"`
+}
+
+type testAudit struct {
+	By string `graphql:"by"`
+}
+
+type testInput struct {
+	testTimestamps
+	*testAudit
+	ID       int         `graphql:"id"` // shadows testTimestamps.ID
+	Title    string      `graphql:"title"`
+	State    string      `graphql:"state,enum"`
+	Labels   []testOrder `graphql:"labels,omitempty,enum"`
+	Body     *string     `graphql:"body,omitempty"`
+	Internal string      `graphql:"-"`
+	Raw      string
+	secret   string
+}
+
+func TestArgumentAny_structTags(t *testing.T) {
+	arg, err := ArgumentAny("input", testInput{ID: 1, Title: "t", State: "OPEN", Internal: "x", secret: "y"})
+	assert.Nil(t, err)
+	assert.Nil(t, checkValue(arg.Value))
+	assert.Equal(t, `input:{id:1,title:"t",state:OPEN,Raw:""}`, tokenString(arg.tokens))
+
+	body := "b"
+	arg, err = ArgumentAny("input", &testInput{
+		testTimestamps: testTimestamps{CreatedAt: "now"},
+		testAudit:      &testAudit{By: "me"},
+		Labels:         []testOrder{"BUG", "UI"},
+		Body:           &body,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, `input:{createdAt:"now",by:"me",id:0,title:"",state:,labels:[BUG,UI],body:"b",Raw:""}`, tokenString(arg.tokens))
+	assert.IsType(t, InvalidEnumValueErr{}, errors.Cause(checkValue(arg.Value)))
+
+	// fields of the same name at the same depth without a single tagged one are skipped
+	type a struct{ X, Y int }
+	type b struct {
+		X int
+		Y int `graphql:"Y"`
+	}
+	arg, err = ArgumentAny("input", struct {
+		a
+		b
+	}{a{1, 2}, b{3, 4}})
+	assert.Nil(t, err)
+	assert.Equal(t, `input:{Y:4}`, tokenString(arg.tokens))
+
+	_, err = ArgumentAny("input", struct {
+		Price complex64 `graphql:"price"`
+	}{})
+	assert.Equal(t, "Argument (0+0i) of Type complex64 at input.price is not supported", err.Error())
+}
+
+type testPoint struct {
+	X, Y int
+}
+
+func (p testPoint) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf(`{"y":%d,"x":%d,"tags":["a",null,1.5e3,true]}`, p.Y, p.X)), nil
+}
+
+// MarshalText is ignored, MarshalJSON wins
+func (p testPoint) MarshalText() ([]byte, error) {
+	return []byte("text"), nil
+}
+
+type testLevel int
+
+func (l *testLevel) MarshalText() ([]byte, error) {
+	if *l < 0 {
+		return nil, errors.New("negative level")
+	}
+	return []byte("LEVEL_" + strconv.Itoa(int(*l))), nil
+}
+
+func TestArgumentAny_marshalers(t *testing.T) {
+	arg, err := ArgumentAny("p", testPoint{1, 2})
+	assert.Nil(t, err)
+	assert.Nil(t, checkValue(arg.Value))
+	assert.Equal(t, `p:{y:2,x:1,tags:["a",null,1.5e3,true]}`, tokenString(arg.tokens))
+
+	arg, err = ArgumentAny("at", time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC))
+	assert.Nil(t, err)
+	assert.Equal(t, `at:"2018-01-02T03:04:05Z"`, tokenString(arg.tokens))
+
+	arg, err = ArgumentAny("ip", net.IPv4(10, 0, 0, 1))
+	assert.Nil(t, err)
+	assert.Equal(t, `ip:"10.0.0.1"`, tokenString(arg.tokens))
+
+	// the pointer receiver is used for addressable values only
+	level := testLevel(3)
+	arg, err = ArgumentAny("level", &level)
+	assert.Nil(t, err)
+	assert.Equal(t, `level:"LEVEL_3"`, tokenString(arg.tokens))
+	arg, err = ArgumentAny("level", level)
+	assert.Nil(t, err)
+	assert.Equal(t, `level:3`, tokenString(arg.tokens))
+
+	arg, err = ArgumentAny("input", &struct {
+		Levels []testLevel `graphql:"levels,enum"`
+		Big    json.Number `graphql:"big"`
+	}{Levels: []testLevel{1, 2}, Big: "12345678901234567890"})
+	assert.Nil(t, err)
+	assert.Equal(t, `input:{levels:[LEVEL_1,LEVEL_2],big:12345678901234567890}`, tokenString(arg.tokens))
+
+	_, err = ArgumentAny("n", json.Number("1){x}"))
+	assert.Equal(t, `Argument value at n could not be marshaled: invalid number literal "1){x}"`, err.Error())
+
+	_, err = ArgumentAny("input", map[string]interface{}{"levels": []testLevel{-1}})
+	assert.IsType(t, MarshalValueErr{}, err)
+	assert.Equal(t, "Argument value at input.levels[0] could not be marshaled: negative level", err.Error())
+}
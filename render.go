@@ -0,0 +1,166 @@
+package graphb
+
+import (
+	"strconv"
+	"strings"
+)
+
+// RenderOption implements functional options for StringChan() and JSON().
+type RenderOption func(o *renderOptions)
+
+type renderOptions struct {
+	blockStrings bool
+	shorthand    bool
+	typename     typenameMode
+	pretty       bool
+	indent       string
+	lineBreak    string
+}
+
+func newRenderOptions(options []RenderOption) renderOptions {
+	o := renderOptions{indent: "  ", lineBreak: "\n"}
+	for _, op := range options {
+		op(&o)
+	}
+	return o
+}
+
+// tokenSink receives the tokens of a rendering in order. Every node prints its tokens synchronously to a sink.
+type tokenSink func(token string)
+
+// tokenChan returns a closed channel buffered with the tokens printed by print. It backs the StringChan methods
+// without starting a goroutine, so that a consumer which stops reading early leaks nothing.
+func tokenChan(print func(tokenSink)) <-chan string {
+	var tokens []string
+	print(func(token string) {
+		tokens = append(tokens, token)
+	})
+	ch := make(chan string, len(tokens))
+	for _, token := range tokens {
+		ch <- token
+	}
+	close(ch)
+	return ch
+}
+
+// tokenString returns the concatenation of the tokens printed by print.
+func tokenString(print func(tokenSink)) string {
+	var b strings.Builder
+	print(func(token string) {
+		b.WriteString(token)
+	})
+	return b.String()
+}
+
+// skipFirst returns a sink which drops the first token and passes the others to sink.
+func skipFirst(sink tokenSink) tokenSink {
+	skipped := false
+	return func(token string) {
+		if skipped {
+			sink(token)
+		}
+		skipped = true
+	}
+}
+
+// print prints a valid Query, Fragment or Document, or a Field if field is true, with the options applied.
+// Options which transform the node itself, such as OfTypename and OfShorthand, are applied by the node.
+func (o renderOptions) print(node func(tokenSink), field bool, sink tokenSink) {
+	if o.pretty {
+		var tokens []string
+		o.printMinified(node, func(token string) {
+			tokens = append(tokens, token)
+		})
+		sink(pretty(tokens, field, o.indent, o.lineBreak))
+		return
+	}
+	o.printMinified(node, sink)
+}
+
+func (o renderOptions) printMinified(node func(tokenSink), sink tokenSink) {
+	if o.blockStrings {
+		next := sink
+		sink = func(token string) {
+			next(blockStringToken(token))
+		}
+	}
+	node(sink)
+}
+
+// OfBlockStrings returns a RenderOption which renders multi-line string values as block strings, e.g.
+//
+//	"""
+//	# Title
+//	Body
+//	"""
+//
+// A string value which a block string can not represent exactly, such as one with leading blank lines, stays quoted.
+// See: https://spec.graphql.org/October2021/#sec-String-Value.Block-Strings
+func OfBlockStrings() RenderOption {
+	return func(o *renderOptions) {
+		o.blockStrings = true
+	}
+}
+
+// OfShorthand returns a RenderOption which renders a query without name, variables and directives in the shorthand
+// form, e.g. {user{name}} instead of query{user{name}}. A Document uses the shorthand only for its single operation.
+// The option has no effect on other operations, fields and fragments.
+// See: https://spec.graphql.org/October2021/#sec-Language.Operations
+func OfShorthand() RenderOption {
+	return func(o *renderOptions) {
+		o.shorthand = true
+	}
+}
+
+/////////////
+// Helpers //
+/////////////
+
+// blockStringToken converts a multi-line string value token into a block string. It returns other tokens as they are.
+func blockStringToken(token string) string {
+	if strings.HasPrefix(token, `"`) && strings.Contains(token, `\n`) {
+		if s, err := strconv.Unquote(token); err == nil && strings.Contains(s, "\n") && printableAsBlockString(s) {
+			return blockString(s)
+		}
+	}
+	return token
+}
+
+// printableAsBlockString reports whether a block string can represent s exactly.
+// The block string value removes leading and trailing blank lines, common indentation and normalizes line terminators.
+// It is ported from graphql-js.
+func printableAsBlockString(s string) bool {
+	isEmptyLine := true
+	hasIndent := false
+	hasCommonIndent := true
+	seenNonEmptyLine := false
+	for _, r := range s {
+		switch {
+		case r == '\r':
+			return false // \r and \r\n would become \n
+		case r == '\n':
+			if isEmptyLine && !seenNonEmptyLine {
+				return false // leading blank line
+			}
+			seenNonEmptyLine = true
+			isEmptyLine = true
+			hasIndent = false
+		case r == '\t' || r == ' ':
+			hasIndent = hasIndent || isEmptyLine
+		case r < 0x20:
+			return false // non-printable characters
+		default:
+			hasCommonIndent = hasCommonIndent && hasIndent
+			isEmptyLine = false
+		}
+	}
+	if isEmptyLine {
+		return false // trailing blank line
+	}
+	return !(hasCommonIndent && seenNonEmptyLine) // common indentation
+}
+
+// blockString returns the multi-line s as a block string. s must be printable as a block string.
+func blockString(s string) string {
+	return `"""` + "\n" + strings.Replace(s, `"""`, `\"""`, -1) + "\n" + `"""`
+}
@@ -0,0 +1,145 @@
+package graphb
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOfBlockStrings(t *testing.T) {
+	q := MakeQuery(TypeMutation).SetFields(
+		MakeField("createPost").SetArguments(
+			ArgumentString("title", "single line"),
+			ArgumentString("body", "# Title\n\nSome \"quoted\" text with \"\"\" inside.\n  indented\tline"),
+			ArgumentStringSlice("notes", "a\nb", "\nleading blank line"),
+		),
+	)
+	strCh, err := q.StringChan(OfBlockStrings())
+	assert.Nil(t, err)
+	assert.Equal(t, "mutation{createPost(title:\"single line\",body:\"\"\"\n# Title\n\nSome \"quoted\" text with \\\"\"\" inside.\n  indented\tline\n\"\"\",notes:[\"\"\"\na\nb\n\"\"\",\"\\nleading blank line\"])}", StringFromChan(strCh))
+
+	s, err := q.JSON(OfBlockStrings())
+	assert.Nil(t, err)
+	assert.Equal(t, `{"query":"mutation{createPost(title:\"single line\",body:\"\"\"\n# Title\n\nSome \"quoted\" text with \\\"\"\" inside.\n  indented\tline\n\"\"\",notes:[\"\"\"\na\nb\n\"\"\",\"\\nleading blank line\"])}"}`, s)
+
+	strCh, err = q.Fields[0].StringChan(OfBlockStrings())
+	assert.Nil(t, err)
+	assert.Contains(t, StringFromChan(strCh), "notes:[\"\"\"\na\nb\n\"\"\",")
+}
+
+func Test_printableAsBlockString(t *testing.T) {
+	for s, printable := range map[string]bool{
+		"a\nb":            true,
+		"a\n  b\n\tc":     true,
+		"  a\nb":          true,
+		"a\n\n   \nb":     true,
+		"\na":             false, // leading blank line
+		"   \na":          false, // leading blank line
+		"a\n":             false, // trailing blank line
+		"a\n  ":           false, // trailing blank line
+		"  a\n  b":        false, // common indentation
+		"a\r\nb":          false,
+		"a\nb\x00":        false,
+		"a\nb\x1b[31mred": false,
+	} {
+		assert.Equal(t, printable, printableAsBlockString(s), "%q", s)
+	}
+}
+
+func FuzzBlockString(f *testing.F) {
+	for _, s := range []string{"a\nb", "a\n  b", "  a\nb", "a\n\"\"\"\nb", "a\\\"\"\"\nb\"", "a\nb\\", "\ta\n\n b"} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		if !utf8.ValidString(s) || !strings.Contains(s, "\n") || !printableAsBlockString(s) {
+			return
+		}
+		if v := parseBlockString(blockString(s)); v != s {
+			t.Fatalf("blockString(%q) = %s parses to %q", s, blockString(s), v)
+		}
+	})
+}
+
+// parseBlockString returns the value of a block string according to the spec.
+// See: https://spec.graphql.org/October2021/#BlockStringValue()
+func parseBlockString(s string) string {
+	raw := strings.Replace(s[3:len(s)-3], `\"""`, `"""`, -1)
+	lines := strings.Split(strings.Replace(strings.Replace(raw, "\r\n", "\n", -1), "\r", "\n", -1), "\n")
+	commonIndent := -1
+	for _, line := range lines[1:] {
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent < len(line) && (commonIndent == -1 || indent < commonIndent) {
+			commonIndent = indent
+		}
+	}
+	if commonIndent > 0 {
+		for i := 1; i < len(lines); i++ {
+			if len(lines[i]) >= commonIndent {
+				lines[i] = lines[i][commonIndent:]
+			} else {
+				lines[i] = ""
+			}
+		}
+	}
+	for len(lines) > 0 && strings.TrimLeft(lines[0], " \t") == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimLeft(lines[len(lines)-1], " \t") == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestOfShorthand(t *testing.T) {
+	q := MakeQuery(TypeQuery).SetFields(MakeField("user").SetFields(Fields("name")...))
+	s, err := q.JSON(OfShorthand())
+	assert.Nil(t, err)
+	assert.Equal(t, `{"query":"{user{name}}"}`, s)
+
+	fr := MakeFragment("F", "User").SetFields(MakeField("id"))
+	q = NewQuery("QUERY", OfField("me", OfFields("name")), OfFragmentSpread(fr))
+	strCh, err := q.StringChan(OfShorthand())
+	assert.Nil(t, err)
+	assert.Equal(t, "{me{name},...F}fragment F on User{id}", StringFromChan(strCh))
+
+	strCh, err = MakeDocument(q).AddFragments(fr).StringChan(OfShorthand())
+	assert.Nil(t, err)
+	assert.Equal(t, "{me{name},...F}fragment F on User{id}", StringFromChan(strCh))
+
+	for _, q := range []*Query{
+		MakeQuery(TypeQuery).SetName("Q").SetFields(MakeField("x")),
+		MakeQuery(TypeQuery).SetVariables(Variable("v", NamedType("Int"))).SetFields(MakeField("x").SetArguments(ArgumentVariable("v", "v"))),
+		MakeQuery(TypeQuery).SetDirectives(MakeDirective("live")).SetFields(MakeField("x")),
+		MakeQuery(TypeMutation).SetFields(MakeField("x")),
+	} {
+		strCh, err := q.StringChan(OfShorthand())
+		assert.Nil(t, err)
+		plain, _ := q.StringChan()
+		assert.Equal(t, StringFromChan(plain), StringFromChan(strCh))
+	}
+
+	// validation is kept
+	_, err = MakeQuery(TypeQuery).SetFields(MakeField("1x")).StringChan(OfShorthand())
+	assert.IsType(t, InvalidNameErr{}, errors.Cause(err))
+	_, err = MakeDocument(MakeQuery(TypeQuery).SetFields(MakeField("x")), MakeQuery(TypeQuery).SetFields(MakeField("y"))).StringChan(OfShorthand())
+	assert.IsType(t, AnonymousOperationErr{}, errors.Cause(err))
+}
+
+func TestStringChan_synchronous(t *testing.T) {
+	before := runtime.NumGoroutine()
+	strCh, err := reportQuery().StringChan()
+	assert.Nil(t, err)
+	assert.Equal(t, "query", <-strCh)
+	// the consumer stops reading early, but no goroutine renders the rest
+	assert.Equal(t, before, runtime.NumGoroutine())
+
+	var tokens []string
+	MakeField("a").SetArguments(ArgumentIntSlice("ids", 1, 2)).SetFields(MakeField("b")).tokens(func(token string) {
+		tokens = append(tokens, token)
+	})
+	assert.Equal(t, []string{"a", "(", "ids", ":", "[", "1", ",", "2", "]", ")", "{", "b", "}"}, tokens)
+}
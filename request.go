@@ -0,0 +1,82 @@
+package graphb
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Request is the body of a GraphQL request over HTTP, see https://graphql.github.io/graphql-over-http/.
+// Build it by Query.Request, Document.Request or Template.Request, and marshal it by encoding/json.
+type Request struct {
+	Query         string
+	OperationName string                 // OperationName is omitted when empty.
+	Variables     map[string]interface{} // Variables is omitted when empty. The values are marshaled by encoding/json.
+	Extensions    map[string]interface{} // Extensions is omitted when empty, e.g. the hash of a persisted query.
+}
+
+// MarshalJSON implements json.Marshaler. The fields are "query", "operationName", "variables" and "extensions" in this order.
+func (r Request) MarshalJSON() ([]byte, error) {
+	var b strings.Builder
+	jw := jsonWriter{w: &b}
+	jw.write(`{"query":"`)
+	jw.writeEscaped(r.Query)
+	jw.write(`"`)
+	if r.OperationName != "" {
+		jw.write(`,"operationName":"`)
+		jw.writeEscaped(r.OperationName)
+		jw.write(`"`)
+	}
+	for _, field := range []struct {
+		name  string
+		value map[string]interface{}
+	}{{"variables", r.Variables}, {"extensions", r.Extensions}} {
+		if len(field.value) == 0 {
+			continue
+		}
+		value, err := json.Marshal(field.value)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		jw.write(`,"` + field.name + `":`)
+		jw.write(string(value))
+	}
+	jw.write(`}`)
+	return []byte(b.String()), errors.WithStack(jw.err)
+}
+
+// Request returns the Request of this Query with the given variables. Its operation name is the name of the query.
+// Every variable must be defined by the query, and every variable of a non-null type without default value must be given.
+// Options such as OfBlockStrings configure the rendering.
+func (q *Query) Request(variables map[string]interface{}, options ...RenderOption) (Request, error) {
+	t, err := q.Compile(options...)
+	if err != nil {
+		return Request{}, errors.WithStack(err)
+	}
+	return t.Request(variables)
+}
+
+// Request returns the Request of this Document with the given variables, where operationName selects the operation to execute.
+// operationName may be empty only when the document contains a single operation.
+// The variables are checked against the selected operation the same way as Query.Request does.
+func (d *Document) Request(operationName string, variables map[string]interface{}, options ...RenderOption) (Request, error) {
+	if err := d.checkRequest(operationName); err != nil {
+		return Request{}, errors.WithStack(err)
+	}
+	q := d.Operations[0]
+	if operationName != "" {
+		q = d.GetOperation(operationName)
+	}
+	if err := checkVariableValues(requiredVariables(q.Variables), variables); err != nil {
+		return Request{}, errors.WithStack(err)
+	}
+	o := newRenderOptions(options)
+	return Request{
+		Query: tokenString(func(sink tokenSink) {
+			d.render(o, sink)
+		}),
+		OperationName: operationName,
+		Variables:     variables,
+	}, nil
+}
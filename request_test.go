@@ -0,0 +1,74 @@
+package graphb
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequest_MarshalJSON(t *testing.T) {
+	b, err := json.Marshal(Request{Query: `{user(name:"<a>"){id}}`})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"query":"{user(name:\"\u003ca\u003e\"){id}}"}`, string(b))
+
+	b, err = json.Marshal(&Request{
+		Query:         "query User($id:ID){user(id:$id){id}}",
+		OperationName: "User",
+		Variables:     map[string]interface{}{"id": "1"},
+		Extensions:    map[string]interface{}{"persistedQuery": map[string]interface{}{"version": 1}},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"query":"query User($id:ID){user(id:$id){id}}","operationName":"User","variables":{"id":"1"},"extensions":{"persistedQuery":{"version":1}}}`, string(b))
+
+	var body struct {
+		Query         string
+		OperationName string
+		Variables     map[string]string
+	}
+	assert.Nil(t, json.Unmarshal(b, &body))
+	assert.Equal(t, "User", body.OperationName)
+	assert.Equal(t, "1", body.Variables["id"])
+
+	_, err = json.Marshal(Request{Variables: map[string]interface{}{"c": make(chan int)}})
+	assert.IsType(t, &json.MarshalerError{}, err)
+}
+
+func TestQuery_Request(t *testing.T) {
+	q := MakeQuery(TypeQuery).SetName("User").AddVariables(Variable("id", NonNullType(NamedType("ID")))).
+		SetFields(MakeField("user").SetArguments(ArgumentVariable("id", "id")).SetFields(MakeField("id")))
+	r, err := q.Request(map[string]interface{}{"id": "1"})
+	assert.Nil(t, err)
+	assert.Equal(t, Request{Query: "query User($id:ID!){user(id:$id){id}}", OperationName: "User", Variables: map[string]interface{}{"id": "1"}}, r)
+
+	_, err = q.Request(nil)
+	assert.Equal(t, MissingVariableErr{"id"}, errors.Cause(err))
+
+	_, err = MakeQuery(TypeQuery).SetName("1").Request(nil)
+	assert.IsType(t, InvalidNameErr{}, errors.Cause(err))
+}
+
+func TestDocument_Request(t *testing.T) {
+	d := MakeDocument(
+		MakeQuery(TypeQuery).SetName("A").AddVariables(Variable("id", NonNullType(NamedType("ID")))).
+			SetFields(MakeField("user").SetArguments(ArgumentVariable("id", "id")).SetFields(MakeField("id"))),
+		MakeQuery(TypeQuery).SetName("B").SetFields(MakeField("me").SetFields(MakeField("id"))),
+	)
+	r, err := d.Request("A", map[string]interface{}{"id": 1})
+	assert.Nil(t, err)
+	b, err := json.Marshal(r)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"query":"query A($id:ID!){user(id:$id){id}}query B{me{id}}","operationName":"A","variables":{"id":1}}`, string(b))
+
+	_, err = d.Request("B", map[string]interface{}{"id": 1})
+	assert.Equal(t, UndefinedVariableErr{"id"}, errors.Cause(err))
+	_, err = d.Request("A", nil)
+	assert.Equal(t, MissingVariableErr{"id"}, errors.Cause(err))
+	_, err = d.Request("", nil)
+	assert.Equal(t, UnknownOperationErr{""}, errors.Cause(err))
+
+	r, err = MakeDocument(MakeQuery(TypeQuery).SetFields(MakeField("me"))).Request("", nil, OfShorthand())
+	assert.Nil(t, err)
+	assert.Equal(t, Request{Query: "{me}"}, r)
+}
@@ -0,0 +1,136 @@
+package graphb
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Template is a compiled Query whose request body only varies by the values of its variables.
+// A Template is immutable and safe for concurrent use. See Query.Compile.
+type Template struct {
+	name      string
+	query     string
+	prefix    string          // prefix is the request body up to the end of the "query" field, e.g. {"query":"..."
+	variables map[string]bool // variables maps the names of defined variables to whether they are required.
+}
+
+// Compile checks and renders the query once and returns a Template which produces its request bodies.
+// Later changes to the query do not affect the Template. Values which change from call to call should be variables.
+func (q *Query) Compile(options ...RenderOption) (*Template, error) {
+	if err := q.check(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	o := newRenderOptions(options)
+	t := &Template{
+		name:      q.Name,
+		query:     tokenString(func(sink tokenSink) { q.render(o, sink) }),
+		variables: requiredVariables(q.Variables),
+	}
+	var b strings.Builder
+	jw := jsonWriter{w: &b}
+	jw.write(`{"query":"`)
+	jw.writeEscaped(t.query)
+	jw.write(`"`)
+	t.prefix = b.String()
+	return t, nil
+}
+
+// Query returns the rendered query.
+func (t *Template) Query() string {
+	return t.query
+}
+
+// JSON returns the json request body with "query" and "variables" fields. The variables field is omitted if there is no variable.
+// Every variable must be defined by the query, and every variable of a non-null type without default value must be given.
+// The values are marshaled by encoding/json.
+func (t *Template) JSON(variables map[string]interface{}) (string, error) {
+	var b strings.Builder
+	if _, err := t.WriteJSON(&b, variables); err != nil {
+		return "", errors.WithStack(err)
+	}
+	return b.String(), nil
+}
+
+// WriteJSON writes the json request body of JSON to w and returns the number of bytes written.
+// Nothing is written if the variables are invalid.
+func (t *Template) WriteJSON(w io.Writer, variables map[string]interface{}) (int64, error) {
+	if err := checkVariableValues(t.variables, variables); err != nil {
+		return 0, errors.WithStack(err)
+	}
+	var values []byte
+	if len(variables) > 0 {
+		var err error
+		if values, err = json.Marshal(variables); err != nil {
+			return 0, errors.WithStack(err)
+		}
+	}
+	jw := jsonWriter{w: w}
+	jw.write(t.prefix)
+	if values != nil {
+		jw.write(`,"variables":`)
+		jw.write(string(values))
+	}
+	jw.write(`}`)
+	return jw.n, errors.WithStack(jw.err)
+}
+
+// Request returns the Request of the template with the given variables, see JSON.
+// Its operation name is the name of the compiled query.
+func (t *Template) Request(variables map[string]interface{}) (Request, error) {
+	if err := checkVariableValues(t.variables, variables); err != nil {
+		return Request{}, errors.WithStack(err)
+	}
+	return Request{Query: t.query, OperationName: t.name, Variables: variables}, nil
+}
+
+// requiredVariables maps the names of the variable definitions to whether a value is required, that is,
+// whether the variable is of a non-null type without default value.
+func requiredVariables(definitions []VariableDefinition) map[string]bool {
+	required := make(map[string]bool, len(definitions))
+	for _, v := range definitions {
+		required[v.Name] = v.Type.NonNull && v.DefaultValue == nil
+	}
+	return required
+}
+
+// isNil reports whether v is nil or a nil pointer, map, slice or interface, all of which encode to JSON null.
+func isNil(v interface{}) bool {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// checkVariableValues checks that every variable is defined, and that every required variable is given, see requiredVariables.
+func checkVariableValues(required map[string]bool, variables map[string]interface{}) error {
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names) // report the same error every time
+	for _, name := range names {
+		if _, ok := required[name]; !ok {
+			return errors.WithStack(UndefinedVariableErr{name})
+		}
+	}
+	var missing []string
+	for name, r := range required {
+		if r && isNil(variables[name]) {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return errors.WithStack(MissingVariableErr{missing[0]})
+	}
+	return nil
+}
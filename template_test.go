@@ -0,0 +1,88 @@
+package graphb
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuery_Compile(t *testing.T) {
+	q := MakeQuery(TypeQuery).SetName("User").
+		AddVariables(Variable("id", NonNullType(NamedType("ID"))), Variable("first", NonNullType(NamedType("Int"))).SetDefault(10)).
+		SetFields(MakeField("user").SetArguments(ArgumentVariable("id", "id")).SetFields(
+			MakeField("friends").SetArguments(ArgumentVariable("first", "first")).SetFields(MakeField("name")),
+		))
+	tpl, err := q.Compile()
+	assert.Nil(t, err)
+	assert.Equal(t, `query User($id:ID!,$first:Int!=10){user(id:$id){friends(first:$first){name}}}`, tpl.Query())
+
+	// the template does not change with the query
+	q.Name = "Changed"
+
+	s, err := tpl.JSON(map[string]interface{}{"id": "1", "first": 2})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"query":"query User($id:ID!,$first:Int!=10){user(id:$id){friends(first:$first){name}}}","variables":{"first":2,"id":"1"}}`, s)
+
+	s, err = tpl.JSON(map[string]interface{}{"id": "<1>"})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"query":"query User($id:ID!,$first:Int!=10){user(id:$id){friends(first:$first){name}}}","variables":{"id":"\u003c1\u003e"}}`, s)
+
+	_, err = tpl.JSON(nil)
+	assert.Equal(t, MissingVariableErr{"id"}, errors.Cause(err))
+	assert.Equal(t, "Variable '$id' of a non-null type requires a value", err.Error())
+
+	_, err = tpl.JSON(map[string]interface{}{"id": (*string)(nil)})
+	assert.Equal(t, MissingVariableErr{"id"}, errors.Cause(err))
+	_, err = tpl.JSON(map[string]interface{}{"id": []string(nil)})
+	assert.Equal(t, MissingVariableErr{"id"}, errors.Cause(err))
+
+	_, err = tpl.JSON(map[string]interface{}{"id": "1", "after": "x"})
+	assert.Equal(t, UndefinedVariableErr{"after"}, errors.Cause(err))
+
+	var b strings.Builder
+	n, err := tpl.WriteJSON(&b, map[string]interface{}{"id": make(chan int)})
+	assert.IsType(t, &json.UnsupportedTypeError{}, errors.Cause(err))
+	assert.Equal(t, int64(0), n)
+	assert.Equal(t, "", b.String())
+
+	_, err = MakeQuery(TypeQuery).SetName("1").Compile()
+	assert.IsType(t, InvalidNameErr{}, errors.Cause(err))
+}
+
+func TestTemplate_options(t *testing.T) {
+	q := MakeQuery(TypeQuery).SetFields(MakeField("user").SetFields(MakeField("id")))
+	tpl, err := q.Compile(OfShorthand(), OfTypename())
+	assert.Nil(t, err)
+	s, err := tpl.JSON(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"query":"{user{id,__typename},__typename}"}`, s)
+}
+
+func TestTemplate_concurrent(t *testing.T) {
+	q := MakeQuery(TypeQuery).AddVariables(Variable("id", NamedType("ID"))).
+		SetFields(MakeField("user").SetArguments(ArgumentVariable("id", "id")).SetFields(MakeField("id")))
+	tpl, err := q.Compile()
+	assert.Nil(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			s, err := tpl.JSON(map[string]interface{}{"id": i})
+			assert.Nil(t, err)
+			var body struct {
+				Query     string         `json:"query"`
+				Variables map[string]int `json:"variables"`
+			}
+			assert.Nil(t, json.Unmarshal([]byte(s), &body))
+			assert.Equal(t, tpl.Query(), body.Query)
+			assert.Equal(t, i, body.Variables["id"])
+		}(i)
+	}
+	wg.Wait()
+}
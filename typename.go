@@ -0,0 +1,122 @@
+package graphb
+
+import (
+	"strings"
+)
+
+// typenameField is the name of the meta field which selects the name of the object type.
+const typenameField = "__typename"
+
+type typenameMode int
+
+const (
+	typenameNone        typenameMode = iota
+	typenameAll                      // inject into every selection set with sub-fields
+	typenameExceptRoots              // inject into every selection set with sub-fields but the operation roots
+)
+
+// OfTypename returns a RenderOption which adds __typename to every selection set of fields, fragment definitions
+// and operations, unless the selection set already selects __typename. The root of a subscription is skipped,
+// since a subscription must select a single root field. The top level of inline fragments and of spread fragments
+// is skipped as well, since the selection set around them selects __typename already, or is a skipped root.
+// The Query, Field or Fragment itself is not modified.
+func OfTypename() RenderOption {
+	return func(o *renderOptions) {
+		o.typename = typenameAll
+	}
+}
+
+// OfTypenameExceptRoots is like OfTypename but skips the root selection sets of operations.
+func OfTypenameExceptRoots() RenderOption {
+	return func(o *renderOptions) {
+		o.typename = typenameExceptRoots
+	}
+}
+
+/////////////
+// Helpers //
+/////////////
+
+// typenameInjector copies selection sets with __typename added. Fragments must have been checked for cycles.
+type typenameInjector struct {
+	mode      typenameMode
+	fragments map[*Fragment]*Fragment // copies by original, so that every spread of a fragment keeps referring to the same one
+}
+
+func newTypenameInjector(mode typenameMode) *typenameInjector {
+	return &typenameInjector{mode: mode, fragments: make(map[*Fragment]*Fragment)}
+}
+
+func (t *typenameInjector) query(q *Query) *Query {
+	if t.mode == typenameNone {
+		return q
+	}
+	c := *q
+	c.Fields = t.fields(q.Fields, t.mode == typenameAll && strings.ToLower(string(q.Type)) != string(TypeSubscription))
+	return &c
+}
+
+func (t *typenameInjector) field(f *Field) *Field {
+	if t.mode == typenameNone {
+		return f
+	}
+	c := *f
+	switch {
+	case f.Fragment != nil:
+		c.Fragment = t.fragment(f.Fragment)
+	case f.Inline:
+		// the enclosing selection set selects __typename
+		c.Fields = t.fields(f.Fields, false)
+	case len(f.Fields) > 0:
+		c.Fields = t.fields(f.Fields, true)
+	}
+	return &c
+}
+
+// fragment copies a fragment reached through spreads. Like for inline fragments, the top level is skipped since
+// every selection set around a spread selects __typename, unless it is an operation root which must stay untouched.
+func (t *typenameInjector) fragment(fr *Fragment) *Fragment {
+	if t.mode == typenameNone {
+		return fr
+	}
+	if c, ok := t.fragments[fr]; ok {
+		return c
+	}
+	c := *fr
+	t.fragments[fr] = &c
+	c.Fields = t.fields(fr.Fields, false)
+	return &c
+}
+
+// definition copies a fragment rendered on its own, whose top level is injected as well.
+func (t *typenameInjector) definition(fr *Fragment) *Fragment {
+	if t.mode == typenameNone {
+		return fr
+	}
+	c := *t.fragment(fr)
+	if !selectsTypename(c.Fields) {
+		c.Fields = append(c.Fields[:len(c.Fields):len(c.Fields)], &Field{Name: typenameField})
+	}
+	return &c
+}
+
+func (t *typenameInjector) fields(fields []*Field, inject bool) []*Field {
+	result := make([]*Field, 0, len(fields)+1)
+	for _, f := range fields {
+		result = append(result, t.field(f))
+	}
+	if inject && !selectsTypename(fields) {
+		result = append(result, &Field{Name: typenameField})
+	}
+	return result
+}
+
+// selectsTypename reports whether fields contain a field of response key __typename.
+func selectsTypename(fields []*Field) bool {
+	for _, f := range fields {
+		if f.Fragment == nil && !f.Inline && f.responseKey() == typenameField {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package graphb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOfTypename(t *testing.T) {
+	userParts := MakeFragment("UserParts", "User").SetFields(MakeField("id"), MakeField("friends").SetFields(MakeField("id")))
+	q := MakeQuery(TypeQuery).SetFields(
+		MakeField("me").SetFields(userParts.Spread(), MakeField("avatar").SetFields(MakeField("url"), MakeField("__typename"))),
+		MakeField("search").SetFields(MakeInlineFragment("User").SetFields(userParts.Spread(), MakeField("name"))),
+		MakeField("node").SetFields(MakeField("__typename").SetAlias("kind")),
+		MakeField("count"),
+	)
+
+	strCh, err := q.StringChan(OfTypename())
+	assert.Nil(t, err)
+	assert.Equal(t, "query{me{...UserParts,avatar{url,__typename},__typename},search{...on User{...UserParts,name},__typename},node{kind:__typename,__typename},count,__typename}"+
+		"fragment UserParts on User{id,friends{id,__typename}}", StringFromChan(strCh))
+
+	strCh, err = q.StringChan(OfTypenameExceptRoots())
+	assert.Nil(t, err)
+	assert.Equal(t, "query{me{...UserParts,avatar{url,__typename},__typename},search{...on User{...UserParts,name},__typename},node{kind:__typename,__typename},count}"+
+		"fragment UserParts on User{id,friends{id,__typename}}", StringFromChan(strCh))
+
+	// the query is not modified
+	strCh, err = q.StringChan()
+	assert.Nil(t, err)
+	assert.Equal(t, "query{me{...UserParts,avatar{url,__typename}},search{...on User{...UserParts,name}},node{kind:__typename},count}"+
+		"fragment UserParts on User{id,friends{id}}", StringFromChan(strCh))
+
+	strCh, err = MakeQuery(TypeSubscription).SetFields(MakeField("onEvent").SetFields(MakeField("id"))).StringChan(OfTypename())
+	assert.Nil(t, err)
+	assert.Equal(t, "subscription{onEvent{id,__typename}}", StringFromChan(strCh))
+
+	strCh, err = MakeField("me").SetFields(MakeField("id")).StringChan(OfTypename())
+	assert.Nil(t, err)
+	assert.Equal(t, "me{id,__typename}", StringFromChan(strCh))
+
+	strCh, err = userParts.StringChan(OfTypename())
+	assert.Nil(t, err)
+	assert.Equal(t, "fragment UserParts on User{id,friends{id,__typename},__typename}", StringFromChan(strCh))
+
+	d := MakeDocument(MakeQuery(TypeQuery).SetName("A").SetFields(userParts.Spread()), MakeQuery(TypeQuery).SetName("B").SetFields(MakeField("me").SetFields(userParts.Spread()))).AddFragments(userParts)
+	strCh, err = d.StringChan(OfTypenameExceptRoots())
+	assert.Nil(t, err)
+	assert.Equal(t, "query A{...UserParts}query B{me{...UserParts,__typename}}fragment UserParts on User{id,friends{id,__typename}}", StringFromChan(strCh))
+}
+
+func TestOfTypename_rootSpreads(t *testing.T) {
+	s := MakeFragment("S", "Subscription").SetFields(MakeField("newMessage").SetFields(MakeField("id")))
+	strCh, err := MakeQuery(TypeSubscription).SetFields(s.Spread()).StringChan(OfTypename())
+	assert.Nil(t, err)
+	assert.Equal(t, "subscription{...S}fragment S on Subscription{newMessage{id,__typename}}", StringFromChan(strCh))
+
+	q := MakeFragment("Q", "Query").SetFields(MakeField("me").SetFields(MakeField("id")), MakeField("count"))
+	strCh, err = MakeQuery(TypeQuery).SetFields(q.Spread()).StringChan(OfTypenameExceptRoots())
+	assert.Nil(t, err)
+	assert.Equal(t, "query{...Q}fragment Q on Query{me{id,__typename},count}", StringFromChan(strCh))
+
+	strCh, err = MakeQuery(TypeQuery).SetFields(q.Spread()).StringChan(OfTypename())
+	assert.Nil(t, err)
+	assert.Equal(t, "query{...Q,__typename}fragment Q on Query{me{id,__typename},count}", StringFromChan(strCh))
+}
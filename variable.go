@@ -0,0 +1,131 @@
+package graphb
+
+import (
+	"github.com/pkg/errors"
+)
+
+// VariableType is a GraphQL type reference used by variable definitions, e.g. ID, [Int] or [String!]!.
+// A named type sets Name, a list type sets Elem, and NonNull marks either of them as non-null.
+type VariableType struct {
+	Name    string        // Name is the name of a named type. It is ignored when Elem is not nil.
+	Elem    *VariableType // Elem is the item type of a list type.
+	NonNull bool
+}
+
+func (t VariableType) tokens(sink tokenSink) {
+	if t.Elem != nil {
+		sink(tokenLSB)
+		t.Elem.tokens(sink)
+		sink(tokenRSB)
+	} else {
+		sink(t.Name)
+	}
+	if t.NonNull {
+		sink(tokenBang)
+	}
+}
+
+func (t VariableType) check() error {
+	if t.Elem != nil {
+		return errors.WithStack(t.Elem.check())
+	}
+	if !validName.MatchString(t.Name) {
+		return errors.WithStack(InvalidNameErr{typeName, t.Name})
+	}
+	return nil
+}
+
+// VariableDefinition represents a variable defined by an operation, e.g. $first: Int = 10.
+type VariableDefinition struct {
+	Name string // Name is the variable name without the leading $.
+	Type VariableType
+	// DefaultValue is the default value of the variable. A nil DefaultValue means the variable has no default value,
+	// use Null for a default value of null. It is converted to a GraphQL value the same way as ArgumentAny does.
+	DefaultValue interface{}
+	Directives   []Directive
+}
+
+// Implement directiveContainer
+func (v *VariableDefinition) getDirectives() []Directive {
+	return v.Directives
+}
+
+func (v *VariableDefinition) setDirectives(ds []Directive) {
+	v.Directives = ds
+}
+
+func (v VariableDefinition) tokens(sink tokenSink) {
+	sink(tokenDollar)
+	sink(v.Name)
+	sink(tokenColumn)
+	v.Type.tokens(sink)
+	if v.DefaultValue != nil {
+		// the default value has been validated by check
+		value, _ := valueOf(v.DefaultValue, "$"+v.Name)
+		sink(tokenEqual)
+		valueTokens(value, sink)
+	}
+	directivesTokens(sink, v.Directives)
+}
+
+func (v VariableDefinition) check() error {
+	if !validName.MatchString(v.Name) {
+		return errors.WithStack(InvalidNameErr{variableName, v.Name})
+	}
+	if err := v.Type.check(); err != nil {
+		return errors.WithStack(err)
+	}
+	if v.DefaultValue != nil {
+		value, err := valueOf(v.DefaultValue, "$"+v.Name)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if err := checkValue(value); err != nil {
+			return errors.WithStack(err)
+		}
+		if names := valueVariables(value); len(names) > 0 {
+			return errors.WithStack(NonConstantDefaultErr{v.Name, names[0]})
+		}
+	}
+	if err := checkDirectives(v.Directives, locationVariableDefinition); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+////////////////
+// Public API //
+////////////////
+
+// NamedType returns the type reference of a named type such as ID or a custom input type.
+func NamedType(name string) VariableType {
+	return VariableType{Name: name}
+}
+
+// ListType returns the type reference of a list of the given item type.
+func ListType(elem VariableType) VariableType {
+	return VariableType{Elem: &elem}
+}
+
+// NonNullType returns the non-null version of the given type reference.
+func NonNullType(t VariableType) VariableType {
+	t.NonNull = true
+	return t
+}
+
+// Variable returns a VariableDefinition of given name and type without a default value.
+func Variable(name string, Type VariableType) VariableDefinition {
+	return VariableDefinition{Name: name, Type: Type}
+}
+
+// SetDefault returns a copy of this VariableDefinition with the given default value.
+func (v VariableDefinition) SetDefault(value interface{}) VariableDefinition {
+	v.DefaultValue = value
+	return v
+}
+
+// SetDirectives returns a copy of this VariableDefinition with the given directives.
+func (v VariableDefinition) SetDirectives(directives ...Directive) VariableDefinition {
+	v.Directives = directives
+	return v
+}
@@ -0,0 +1,110 @@
+package graphb
+
+import (
+	"math"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVariableType(t *testing.T) {
+	assert.Equal(t, "ID", tokenString(NamedType("ID").tokens))
+	assert.Equal(t, "ID!", tokenString(NonNullType(NamedType("ID")).tokens))
+	assert.Equal(t, "[Int]", tokenString(ListType(NamedType("Int")).tokens))
+	assert.Equal(t, "[[String!]]!", tokenString(NonNullType(ListType(ListType(NonNullType(NamedType("String"))))).tokens))
+
+	err := ListType(NamedType("In t")).check()
+	assert.IsType(t, InvalidNameErr{}, errors.Cause(err))
+	assert.Equal(t, "'In t' is an invalid type name in GraphQL. A valid name matches /[_A-Za-z][_0-9A-Za-z]*/, see: http://facebook.github.io/graphql/October2016/#sec-Names", err.Error())
+}
+
+func TestVariableDefinition(t *testing.T) {
+	v := Variable("first", NamedType("Int")).SetDefault(10)
+	assert.Nil(t, v.check())
+	assert.Equal(t, "$first:Int=10", tokenString(v.tokens))
+
+	v = Variable("ids", NonNullType(ListType(NamedType("ID"))))
+	assert.Nil(t, v.check())
+	assert.Equal(t, "$ids:[ID]!", tokenString(v.tokens))
+
+	v = Variable("$id", NamedType("ID"))
+	assert.IsType(t, InvalidNameErr{}, errors.Cause(v.check()))
+
+	v = Variable("id", NamedType("ID")).SetDefault(1 + 2i)
+	assert.IsType(t, ArgumentTypeNotSupportedErr{}, errors.Cause(v.check()))
+
+	v = Variable("f", NamedType("Float")).SetDefault(math.Inf(1))
+	assert.IsType(t, InvalidFloatErr{}, errors.Cause(v.check()))
+
+	v = Variable("a", NamedType("Int")).SetDefault(ValueVariable("b"))
+	assert.Equal(t, NonConstantDefaultErr{"a", "b"}, errors.Cause(v.check()))
+	assert.Equal(t, "Default value of variable '$a' refers to variable '$b'. Default values must be constant", v.check().Error())
+
+	v = Variable("in", NamedType("Input")).SetDefault(map[string]interface{}{"ids": []interface{}{1, ValueVariable("id")}})
+	assert.Equal(t, NonConstantDefaultErr{"in", "id"}, errors.Cause(v.check()))
+}
+
+func TestQuery_Variables(t *testing.T) {
+	t.Run("method chaining", func(t *testing.T) {
+		q := MakeQuery(TypeQuery).
+			SetName("GetUser").
+			SetVariables(Variable("id", NonNullType(NamedType("ID")))).
+			AddVariables(Variable("first", NamedType("Int")).SetDefault(10)).
+			SetFields(MakeField("user").SetFields(Fields("name")...))
+		s, err := q.JSON()
+		assert.Nil(t, err)
+		assert.Equal(t, `{"query":"query GetUser($id:ID!,$first:Int=10){user{name}}"}`, s)
+	})
+
+	t.Run("functional options", func(t *testing.T) {
+		q := NewQuery(
+			TypeQuery,
+			OfVariable("id", NonNullType(NamedType("ID"))),
+			OfVariable("names", ListType(NamedType("String")), OfDefault([]string{"a", "b"})),
+			OfField("user", OfFields("name")),
+		)
+		assert.Nil(t, q.E)
+		strCh, err := q.StringChan()
+		assert.Nil(t, err)
+		assert.Equal(t, `query($id:ID!,$names:[String]=["a","b"]){user{name}}`, StringFromChan(strCh))
+
+		q = NewQuery(TypeQuery, OfVariable("1d", NamedType("ID")))
+		assert.IsType(t, InvalidNameErr{}, errors.Cause(q.E))
+
+		q = NewQuery(TypeQuery, OfVariable("id", NamedType("ID"), OfDefault(make(chan int))))
+		assert.IsType(t, ArgumentTypeNotSupportedErr{}, errors.Cause(q.E))
+	})
+
+	t.Run("struct literal", func(t *testing.T) {
+		q := Query{
+			Type: TypeMutation,
+			Variables: []VariableDefinition{
+				{Name: "id", Type: VariableType{Name: "ID", NonNull: true}},
+				{Name: "ok", Type: VariableType{Name: "Boolean"}, DefaultValue: true},
+			},
+			Fields: Fields("x"),
+		}
+		strCh, err := q.StringChan()
+		assert.Nil(t, err)
+		assert.Equal(t, `mutation($id:ID!,$ok:Boolean=true){x}`, StringFromChan(strCh))
+	})
+
+	t.Run("duplicate variables", func(t *testing.T) {
+		q := MakeQuery(TypeQuery).
+			SetVariables(Variable("id", NamedType("ID")), Variable("id", NamedType("String"))).
+			SetFields(MakeField("x"))
+		_, err := q.StringChan()
+		assert.IsType(t, DuplicateVariableErr{}, errors.Cause(err))
+		assert.Equal(t, "Variable '$id' is defined more than once. Variables of an operation must be uniquely named", err.Error())
+	})
+}
+
+func TestVariableDefinition_nullDefault(t *testing.T) {
+	v := Variable("avatar", NamedType("String"))
+	assert.Equal(t, "$avatar:String", tokenString(v.tokens))
+
+	v = v.SetDefault(Null)
+	assert.Nil(t, v.check())
+	assert.Equal(t, "$avatar:String=null", tokenString(v.tokens))
+}
@@ -0,0 +1,136 @@
+package graphb
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// writeTokens writes every token printed by print to w and returns the number of bytes written.
+// Tokens printed after an error are dropped.
+func writeTokens(w io.Writer, print func(tokenSink)) (int64, error) {
+	var n int64
+	var err error
+	print(func(token string) {
+		if err != nil {
+			return
+		}
+		var m int
+		m, err = io.WriteString(w, token)
+		n += int64(m)
+	})
+	return n, errors.WithStack(err)
+}
+
+// writeJSON writes a JSON request body with the "query" field of the tokens printed by print,
+// and the "operationName" field unless operationName is empty.
+func writeJSON(w io.Writer, print func(tokenSink), operationName string) (int64, error) {
+	jw := jsonWriter{w: w}
+	jw.write(`{"query":"`)
+	print(jw.writeEscaped)
+	jw.write(`"`)
+	if operationName != "" {
+		jw.write(`,"operationName":`)
+		b, err := json.Marshal(operationName)
+		if err != nil {
+			return jw.n, errors.WithStack(err)
+		}
+		jw.write(string(b))
+	}
+	jw.write(`}`)
+	return jw.n, errors.WithStack(jw.err)
+}
+
+// jsonWriter writes to w and keeps the first error. Its buffer is reused for every escaped token.
+type jsonWriter struct {
+	w   io.Writer
+	n   int64 // n is the number of bytes written to w.
+	err error
+	buf []byte
+}
+
+func (jw *jsonWriter) write(s string) {
+	if jw.err != nil {
+		return
+	}
+	n, err := io.WriteString(jw.w, s)
+	jw.n += int64(n)
+	jw.err = err
+}
+
+// writeEscaped writes s escaped as the content of a JSON string the same way as encoding/json does,
+// including the escaping of HTML characters and the replacement of invalid UTF-8.
+func (jw *jsonWriter) writeEscaped(s string) {
+	if jw.err != nil {
+		return
+	}
+	const hex = "0123456789abcdef"
+	b := jw.buf[:0]
+	start := 0
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' && c != '<' && c != '>' && c != '&' {
+				i++
+				continue
+			}
+			b = append(b, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				b = append(b, '\\', c)
+			case '\b':
+				b = append(b, '\\', 'b')
+			case '\f':
+				b = append(b, '\\', 'f')
+			case '\n':
+				b = append(b, '\\', 'n')
+			case '\r':
+				b = append(b, '\\', 'r')
+			case '\t':
+				b = append(b, '\\', 't')
+			default:
+				b = append(b, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			b = append(b, s[start:i]...)
+			b = append(b, `\ufffd`...)
+			i += size
+			start = i
+			continue
+		}
+		if r == '\u2028' || r == '\u2029' {
+			b = append(b, s[start:i]...)
+			b = append(b, '\\', 'u', '2', '0', '2', hex[r&0xF])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	if start == 0 {
+		jw.write(s)
+		return
+	}
+	b = append(b, s[start:]...)
+	jw.buf = b
+	n, err := jw.w.Write(b)
+	jw.n += int64(n)
+	jw.err = err
+}
+
+// jsonReader returns a reader of the JSON request body of the tokens printed by print, see writeJSON.
+// The body is rendered before jsonReader returns, so that later changes of the printed tree do not affect it.
+func jsonReader(print func(tokenSink), operationName string) (io.Reader, error) {
+	var b bytes.Buffer
+	if _, err := writeJSON(&b, print, operationName); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return bytes.NewReader(b.Bytes()), nil
+}
@@ -0,0 +1,119 @@
+package graphb
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// failingWriter fails after n bytes.
+type failingWriter struct {
+	n int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		n := w.n
+		w.n = 0
+		return n, io.ErrShortWrite
+	}
+	w.n -= len(p)
+	return len(p), nil
+}
+
+func TestQuery_Render(t *testing.T) {
+	q := MakeQuery(TypeQuery).SetName("Q").SetFields(MakeField("user").SetArguments(ArgumentString("name", "<a> & \"b\" ")).SetFields(MakeField("id")))
+
+	var b bytes.Buffer
+	n, err := q.WriteTo(&b)
+	assert.Nil(t, err)
+	assert.Equal(t, `query Q{user(name:"<a> & \"b\"`+" "+`"){id}}`, b.String())
+	assert.Equal(t, int64(b.Len()), n)
+
+	b.Reset()
+	n, err = q.Render(&b, OfPretty())
+	assert.Nil(t, err)
+	assert.Equal(t, "query Q {\n  user(name: \"<a> & \\\"b\\\" \") {\n    id\n  }\n}", b.String())
+	assert.Equal(t, int64(b.Len()), n)
+
+	// WriteJSON writes what encoding/json would
+	b.Reset()
+	n, err = q.WriteJSON(&b)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(b.Len()), n)
+	expected, _ := json.Marshal(map[string]string{"query": `query Q{user(name:"<a> & \"b\"` + " " + `"){id}}`})
+	assert.Equal(t, string(expected), b.String())
+	s, err := q.JSON()
+	assert.Nil(t, err)
+	assert.Equal(t, string(expected), s)
+
+	r, err := q.JSONReader()
+	assert.Nil(t, err)
+	req, err := http.NewRequest(http.MethodPost, "http://localhost/graphql", r)
+	assert.Nil(t, err)
+	body, err := ioutil.ReadAll(req.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, string(expected), string(body))
+
+	assert.Equal(t, int64(len(expected)), req.ContentLength)
+
+	// the rendering stops when the writer fails
+	n, err = q.WriteJSON(&failingWriter{20})
+	assert.Equal(t, io.ErrShortWrite, errors.Cause(err))
+	assert.Equal(t, int64(20), n)
+
+	// the reader is not affected by later changes of the query
+	r, err = q.JSONReader()
+	assert.Nil(t, err)
+	q.Fields[0].Name = "bad name"
+	body, err = ioutil.ReadAll(r)
+	assert.Nil(t, err)
+	assert.Equal(t, string(expected), string(body))
+
+	// nothing is written for an invalid query
+	b.Reset()
+	_, err = MakeQuery(TypeQuery).SetFields(MakeField("1")).WriteJSON(&b)
+	assert.IsType(t, InvalidNameErr{}, errors.Cause(err))
+	_, err = MakeQuery(TypeQuery).SetFields(MakeField("1")).JSONReader()
+	assert.IsType(t, InvalidNameErr{}, errors.Cause(err))
+	assert.Equal(t, 0, b.Len())
+}
+
+func TestRender(t *testing.T) {
+	fr := MakeFragment("F", "T").SetFields(MakeField("x"))
+	d := MakeDocument(MakeQuery(TypeQuery).SetName("A").SetFields(fr.Spread()), MakeQuery(TypeQuery).SetName("B").SetFields(MakeField("y")))
+
+	var b strings.Builder
+	_, err := d.WriteTo(&b)
+	assert.Nil(t, err)
+	assert.Equal(t, "query A{...F}query B{y}fragment F on T{x}", b.String())
+
+	b.Reset()
+	_, err = d.WriteJSON(&b, "B")
+	assert.Nil(t, err)
+	assert.Equal(t, `{"query":"query A{...F}query B{y}fragment F on T{x}","operationName":"B"}`, b.String())
+
+	r, err := d.JSONReader("A", OfShorthand())
+	assert.Nil(t, err)
+	body, _ := ioutil.ReadAll(r)
+	assert.Equal(t, `{"query":"query A{...F}query B{y}fragment F on T{x}","operationName":"A"}`, string(body))
+	_, err = d.JSONReader("C")
+	assert.IsType(t, UnknownOperationErr{}, errors.Cause(err))
+
+	b.Reset()
+	_, err = fr.WriteTo(&b)
+	assert.Nil(t, err)
+	assert.Equal(t, "fragment F on T{x}", b.String())
+
+	b.Reset()
+	_, err = MakeField("a").SetFields(MakeField("b")).Render(&b, OfTypename())
+	assert.Nil(t, err)
+	assert.Equal(t, "a{b,__typename}", b.String())
+}